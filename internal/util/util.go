@@ -0,0 +1,29 @@
+// Package util provides helpers shared by the drawers of packages monitor and plot.
+package util
+
+import (
+	"math"
+
+	"github.com/gopxl/pixel/v2/ext/text"
+	"golang.org/x/image/font/basicfont"
+)
+
+// DefaultFont is the font atlas for text drawn directly with OpenGL.
+var DefaultFont = text.NewAtlas(basicfont.Face7x13, text.ASCII)
+
+var preferredTicks = []float64{1, 2, 5, 10}
+
+// CalcTicksStep calculates the optimal step size for axis ticks.
+func CalcTicksStep(max float64, desired int) float64 {
+	steps := float64(desired)
+	approxStep := float64(max) / (steps - 1)
+	stepPower := math.Pow(10, -math.Floor(math.Log10(approxStep)))
+	normalizedStep := approxStep * stepPower
+	for _, s := range preferredTicks {
+		if s >= normalizedStep {
+			normalizedStep = s
+			break
+		}
+	}
+	return normalizedStep / stepPower
+}
@@ -0,0 +1,13 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcTicksStep(t *testing.T) {
+	assert.InDelta(t, 2.0, CalcTicksStep(10, 6), 1e-9)
+	assert.InDelta(t, 20.0, CalcTicksStep(100, 8), 1e-9)
+	assert.InDelta(t, 0.05, CalcTicksStep(0.3, 7), 1e-9)
+}
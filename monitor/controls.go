@@ -9,6 +9,7 @@ import (
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/gopxl/pixel/v2/ext/imdraw"
 	"github.com/gopxl/pixel/v2/ext/text"
+	"github.com/mlange-42/ark-pixel/internal/util"
 	"github.com/mlange-42/ark-tools/app"
 	"github.com/mlange-42/ark/ecs"
 )
@@ -39,7 +40,7 @@ func (c *Controls) Initialize(w *ecs.World, _ *opengl.Window) {
 	}
 
 	c.drawer = *imdraw.New(nil)
-	c.text = text.New(px.V(0, 0), defaultFont)
+	c.text = text.New(px.V(0, 0), util.DefaultFont)
 
 }
 
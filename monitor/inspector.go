@@ -8,6 +8,7 @@ import (
 	px "github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/gopxl/pixel/v2/ext/text"
+	"github.com/mlange-42/ark-pixel/internal/util"
 	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 )
@@ -35,8 +36,8 @@ type Inspector struct {
 func (i *Inspector) Initialize(w *ecs.World, _ *opengl.Window) {
 	i.selectedRes = ecs.NewResource[resource.SelectedEntity](w)
 
-	i.text = text.New(px.V(0, 0), defaultFont)
-	i.helpText = text.New(px.V(0, 0), defaultFont)
+	i.text = text.New(px.V(0, 0), util.DefaultFont)
+	i.helpText = text.New(px.V(0, 0), util.DefaultFont)
 
 	i.text.AlignedTo(px.BottomRight)
 	i.helpText.AlignedTo(px.BottomRight)
@@ -11,6 +11,7 @@ import (
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/gopxl/pixel/v2/ext/imdraw"
 	"github.com/gopxl/pixel/v2/ext/text"
+	"github.com/mlange-42/ark-pixel/internal/util"
 	"github.com/mlange-42/ark-pixel/window"
 	"github.com/mlange-42/ark/ecs"
 	"github.com/mlange-42/ark/ecs/stats"
@@ -108,12 +109,12 @@ func (m *Monitor) Initialize(w *ecs.World, _ *opengl.Window) {
 
 	m.scale = calcScaleCorrection()
 
-	m.summary = text.New(px.V(0, 0), defaultFont)
+	m.summary = text.New(px.V(0, 0), util.DefaultFont)
 	m.summary.AlignedTo(px.BottomRight)
 
 	m.timeSeries = newTimeSeries(m.PlotCapacity)
 	for i := 0; i < len(m.timeSeries.Text); i++ {
-		m.timeSeries.Text[i] = text.New(px.V(0, 0), defaultFont)
+		m.timeSeries.Text[i] = text.New(px.V(0, 0), util.DefaultFont)
 	}
 	_, _ = fmt.Fprintf(m.timeSeries.Text[tsEntities], "Entities")
 	_, _ = fmt.Fprintf(m.timeSeries.Text[tsEntityCap], "Capacity")
@@ -121,10 +122,10 @@ func (m *Monitor) Initialize(w *ecs.World, _ *opengl.Window) {
 	_, _ = fmt.Fprintf(m.timeSeries.Text[tsMemoryUsed], "Memory used")
 	_, _ = fmt.Fprintf(m.timeSeries.Text[tsTickPerSec], "TPS")
 
-	m.text = text.New(px.V(0, 0), defaultFont).AlignedTo(px.TopRight)
+	m.text = text.New(px.V(0, 0), util.DefaultFont).AlignedTo(px.TopRight)
 	m.text.Color = color.RGBA{200, 200, 200, 255}
 
-	m.textRight = text.New(px.V(0, 0), defaultFont).AlignedTo(px.TopLeft)
+	m.textRight = text.New(px.V(0, 0), util.DefaultFont).AlignedTo(px.TopLeft)
 	m.textRight.Color = color.RGBA{200, 200, 200, 255}
 
 	m.step = 0
@@ -279,7 +280,7 @@ func (m *Monitor) drawProgressBar(win *opengl.Window, tick, maxTicks int64, widt
 
 func (m *Monitor) drawArchetypeScales(win *opengl.Window, x, y, w float64, max int) {
 	dr := &m.drawer
-	step := calcTicksStep(float64(max), 8)
+	step := util.CalcTicksStep(float64(max), 8)
 	if step < 1 {
 		return
 	}
@@ -484,7 +485,7 @@ func (a *archetypes) Update(stats *stats.World) {
 	numNodes := len(stats.Archetypes)
 	for i := range numNodes {
 		node := &stats.Archetypes[i]
-		text := text.New(px.V(0, 0), defaultFont)
+		text := text.New(px.V(0, 0), util.DefaultFont)
 		text.Color = color.RGBA{200, 200, 200, 255}
 		sb := strings.Builder{}
 		sb.WriteString(fmt.Sprintf("              %4d B  ", node.MemoryPerEntity))
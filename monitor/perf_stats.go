@@ -9,6 +9,7 @@ import (
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/gopxl/pixel/v2/ext/imdraw"
 	"github.com/gopxl/pixel/v2/ext/text"
+	"github.com/mlange-42/ark-pixel/internal/util"
 	"github.com/mlange-42/ark/ecs"
 )
 
@@ -36,7 +37,7 @@ func (p *PerfStats) Initialize(w *ecs.World, _ *opengl.Window) {
 
 	p.drawer = *imdraw.New(nil)
 
-	p.summary = text.New(px.V(0, 0), defaultFont)
+	p.summary = text.New(px.V(0, 0), util.DefaultFont)
 	p.summary.AlignedTo(px.BottomRight)
 
 	p.step = 0
@@ -1,16 +1,9 @@
 package monitor
 
 import (
-	"math"
-
-	"github.com/gopxl/pixel/v2/ext/text"
-	"golang.org/x/image/font/basicfont"
 	"gonum.org/v1/plot/vg/vgimg"
 )
 
-var defaultFont = text.NewAtlas(basicfont.Face7x13, text.ASCII)
-
-var preferredTicks = []float64{1, 2, 5, 10}
 var preferredTps = []float64{0, 1, 2, 3, 4, 5, 7, 10, 15, 20, 30, 40, 50, 60, 80, 100, 120, 150, 200, 250, 500, 750, 1000, 2000, 5000, 10000}
 
 // Get the index of an element in a slice.
@@ -28,21 +21,6 @@ func calcScaleCorrection() float64 {
 	return 72.0 / vgimg.DefaultDPI
 }
 
-// Calculate the optimal step size for axis ticks.
-func calcTicksStep(max float64, desired int) float64 {
-	steps := float64(desired)
-	approxStep := float64(max) / (steps - 1)
-	stepPower := math.Pow(10, -math.Floor(math.Log10(approxStep)))
-	normalizedStep := approxStep * stepPower
-	for _, s := range preferredTicks {
-		if s >= normalizedStep {
-			normalizedStep = s
-			break
-		}
-	}
-	return normalizedStep / stepPower
-}
-
 // Calculate TPS when increasing/decreasing it.
 func calcTps(curr float64, increase bool) float64 {
 	ln := len(preferredTps)
@@ -0,0 +1,109 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Default dash pattern of marker lines.
+var markerDashes = []vg.Length{vg.Points(4), vg.Points(3)}
+
+// Marker is a labelled reference line in a plot, like an event or a threshold.
+type Marker struct {
+	Value float64     // Position of the line, on the X axis for vertical and on the Y axis for horizontal lines.
+	Label string      // Label of the line. Optional.
+	Color color.Color // Color of the line and label. Optional, default foreground color of the plot style.
+}
+
+// Annotations is a resource for marking events in plots, like interventions in a model.
+//
+// Systems add event markers to the resource, and plot drawers with events enabled
+// (see e.g. [TimeSeries.Events]) show them as vertical lines.
+// The resource must be added to the world by the user, e.g. with ecs.AddResource.
+type Annotations struct {
+	Events []Marker // Event markers. X positions are in units of the plot's X axis.
+}
+
+// Add an event marker at the given X position.
+func (a *Annotations) Add(x float64, label string) {
+	a.Events = append(a.Events, Marker{Value: x, Label: label})
+}
+
+// markerLines is a plotter for vertical or horizontal marker lines.
+//
+// Vertical lines do not affect the axis ranges, and are only drawn when inside the X range.
+// Horizontal lines extend the Y axis range, so that thresholds are always visible.
+type markerLines struct {
+	Markers    []Marker
+	Horizontal bool
+	LineStyle  draw.LineStyle
+	TextStyle  text.Style
+}
+
+// newMarkerLines creates a plotter for marker lines in the given style, copying the markers.
+func newMarkerLines(markers []Marker, horizontal bool, s *Style) *markerLines {
+	textStyle := plot.New().X.Tick.Label
+	textStyle.Font.Variant = s.Font
+	textStyle.Font.Size = s.TickSize * 0.85
+	textStyle.YAlign = draw.YBottom
+	return &markerLines{
+		Markers:    append([]Marker(nil), markers...),
+		Horizontal: horizontal,
+		LineStyle:  draw.LineStyle{Color: s.Foreground, Width: s.LineWidth, Dashes: markerDashes},
+		TextStyle:  textStyle,
+	}
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (m *markerLines) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	for _, mk := range m.Markers {
+		lineStyle, textStyle := m.LineStyle, m.TextStyle
+		if mk.Color != nil {
+			lineStyle.Color = mk.Color
+		}
+		textStyle.Color = lineStyle.Color
+
+		if m.Horizontal {
+			if mk.Value < p.Y.Min || mk.Value > p.Y.Max {
+				continue
+			}
+			y := trY(mk.Value)
+			c.StrokeLine2(lineStyle, c.Min.X, y, c.Max.X, y)
+			if mk.Label != "" {
+				textStyle.XAlign = draw.XRight
+				c.FillText(textStyle, vg.Point{X: c.Max.X - vg.Points(3), Y: y + vg.Points(2)}, mk.Label)
+			}
+			continue
+		}
+
+		if mk.Value < p.X.Min || mk.Value > p.X.Max {
+			continue
+		}
+		x := trX(mk.Value)
+		c.StrokeLine2(lineStyle, x, c.Min.Y, x, c.Max.Y)
+		if mk.Label != "" {
+			textStyle.Rotation = math.Pi / 2
+			textStyle.XAlign = draw.XRight
+			c.FillText(textStyle, vg.Point{X: x - vg.Points(2), Y: c.Max.Y - vg.Points(3)}, mk.Label)
+		}
+	}
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+func (m *markerLines) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, ymin = math.Inf(1), math.Inf(1)
+	xmax, ymax = math.Inf(-1), math.Inf(-1)
+	if !m.Horizontal {
+		return
+	}
+	for _, mk := range m.Markers {
+		ymin, ymax = math.Min(ymin, mk.Value), math.Max(ymax, mk.Value)
+	}
+	return
+}
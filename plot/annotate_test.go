@@ -0,0 +1,55 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestAnnotations(t *testing.T) {
+	a := Annotations{}
+	a.Add(10, "Harvest")
+	a.Add(20, "")
+	assert.Equal(t, []Marker{{Value: 10, Label: "Harvest"}, {Value: 20}}, a.Events)
+}
+
+func TestMarkerLines(t *testing.T) {
+	markers := []Marker{{Value: 5, Label: "Threshold"}, {Value: -1, Color: color.RGBA{R: 255, A: 255}}}
+	style := LightStyle()
+
+	h := newMarkerLines(markers, true, style)
+	markers[0].Value = 100
+	assert.Equal(t, 5.0, h.Markers[0].Value)
+
+	xmin, xmax, ymin, ymax := h.DataRange()
+	assert.True(t, math.IsInf(xmin, 1))
+	assert.True(t, math.IsInf(xmax, -1))
+	assert.Equal(t, []float64{-1, 5}, []float64{ymin, ymax})
+
+	v := newMarkerLines(markers, false, style)
+	xmin, xmax, ymin, ymax = v.DataRange()
+	assert.True(t, math.IsInf(xmin, 1))
+	assert.True(t, math.IsInf(xmax, -1))
+	assert.True(t, math.IsInf(ymin, 1))
+	assert.True(t, math.IsInf(ymax, -1))
+
+	lines, err := plotter.NewLine(plotter.XYs{{X: 0, Y: 0}, {X: 10, Y: 1}})
+	assert.Nil(t, err)
+	p := plot.New()
+	p.Add(lines, h, v)
+	assert.Equal(t, []float64{0, 10, -1, 5}, []float64{p.X.Min, p.X.Max, p.Y.Min, p.Y.Max})
+
+	path := filepath.Join(t.TempDir(), "markers.svg")
+	assert.Nil(t, saveFigure(&figure{plot: p}, path, 4*vg.Inch, 3*vg.Inch))
+}
+
+func TestMarkerRange(t *testing.T) {
+	lo, hi := markerRange([]Marker{{Value: 5}, {Value: -2}}, 0, 1)
+	assert.Equal(t, []float64{-2, 5}, []float64{lo, hi})
+}
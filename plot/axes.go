@@ -0,0 +1,278 @@
+package plot
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// AxisScale is the scale of a plot axis.
+type AxisScale uint8
+
+const (
+	// LinearScale is a linear axis scale.
+	LinearScale AxisScale = iota
+	// LogScale is a logarithmic axis scale.
+	// Non-positive values are clipped to the lower end of the axis.
+	LogScale
+	// SymLogScale is a symmetric logarithmic axis scale.
+	// It is linear in a range around zero, and supports negative values.
+	SymLogScale
+)
+
+// TickFormat is the label format of axis ticks.
+type TickFormat uint8
+
+const (
+	// DefaultFormat formats tick labels as plain numbers.
+	DefaultFormat TickFormat = iota
+	// SIFormat formats tick labels with SI prefixes, like 1.5k or 20M.
+	SIFormat
+	// PercentFormat formats tick labels as percentages, like 25% for 0.25.
+	PercentFormat
+	// TimeOfDayFormat formats tick labels as time of day, like 06:30.
+	// Uses [Axis.TickDuration] to convert model ticks to time.
+	TimeOfDayFormat
+)
+
+// Axis configuration for plots.
+type Axis struct {
+	Scale        AxisScale              // Axis scale. Optional, default linear.
+	Threshold    float64                // Range around zero where SymLogScale is linear. Optional, default 1.
+	Format       TickFormat             // Tick label format. Optional, default plain numbers.
+	Formatter    func(v float64) string // Custom tick label formatter. Optional, overrides Format.
+	TickDuration time.Duration          // Duration of a model tick, for TimeOfDayFormat. Optional, default one second.
+	Grid         bool                   // Draws grid lines at major ticks.
+	Invert       bool                   // Inverts the axis direction.
+}
+
+// setAxes applies axis configurations to a plot.
+// Must be called before adding data, so that grid lines are drawn below the data.
+func setAxes(p *plot.Plot, x, y Axis, s *Style) {
+	setAxis(&p.X, x)
+	setAxis(&p.Y, y)
+	addGrid(p, x.Grid, y.Grid, s)
+}
+
+// addGrid adds grid lines to a plot, for the X and/or the Y axis.
+func addGrid(p *plot.Plot, x, y bool, s *Style) {
+	if !x && !y {
+		return
+	}
+	grid := plotter.NewGrid()
+	grid.Vertical.Color = s.GridColor
+	grid.Horizontal.Color = s.GridColor
+	if !x {
+		grid.Vertical.Color = nil
+	}
+	if !y {
+		grid.Horizontal.Color = nil
+	}
+	p.Add(grid)
+}
+
+// setAxis applies an axis configuration to a plot axis.
+// Keeps the label adjustments of removeLastTicks and paddedTicks.
+func setAxis(a *plot.Axis, cfg Axis) {
+	var ticker plot.Ticker
+	switch cfg.Scale {
+	case LogScale:
+		a.Scale = logScale{}
+		ticker = logTicks{}
+	case SymLogScale:
+		a.Scale = symLogScale{Threshold: cfg.threshold()}
+		ticker = symLogTicks{Threshold: cfg.threshold()}
+	default:
+		a.Scale = plot.LinearScale{}
+		ticker = plot.DefaultTicks{}
+	}
+	if cfg.Invert {
+		a.Scale = plot.InvertedScale{Normalizer: a.Scale}
+	}
+	if format := cfg.formatter(); format != nil {
+		ticker = formattedTicks{Ticker: ticker, Format: format}
+	}
+
+	switch m := a.Tick.Marker.(type) {
+	case removeLastTicks:
+		m.Ticker = ticker
+		a.Tick.Marker = m
+	case paddedTicks:
+		m.Ticker = ticker
+		a.Tick.Marker = m
+	default:
+		a.Tick.Marker = ticker
+	}
+}
+
+func (a *Axis) threshold() float64 {
+	if a.Threshold <= 0 {
+		return 1
+	}
+	return a.Threshold
+}
+
+// formatter returns the tick label format function, or nil for default labels.
+func (a *Axis) formatter() func(float64) string {
+	if a.Formatter != nil {
+		return a.Formatter
+	}
+	switch a.Format {
+	case SIFormat:
+		return formatSI
+	case PercentFormat:
+		return formatPercent
+	case TimeOfDayFormat:
+		dur := a.TickDuration
+		if dur <= 0 {
+			dur = time.Second
+		}
+		return func(v float64) string { return formatTimeOfDay(v, dur) }
+	default:
+		return nil
+	}
+}
+
+var siPrefixes = []string{"p", "n", "µ", "m", "", "k", "M", "G", "T", "P"}
+
+// formatSI formats a number with an SI prefix.
+func formatSI(v float64) string {
+	if v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	exp := int(math.Floor(math.Log10(math.Abs(v)) / 3))
+	exp = clamp(exp, -4, len(siPrefixes)-5)
+	scaled := v / math.Pow(1000, float64(exp))
+	return strconv.FormatFloat(roundSignificant(scaled, 6), 'g', -1, 64) + siPrefixes[exp+4]
+}
+
+// formatPercent formats a fraction as percentage.
+func formatPercent(v float64) string {
+	return strconv.FormatFloat(roundSignificant(v*100, 6), 'g', -1, 64) + "%"
+}
+
+// formatTimeOfDay formats a model tick as time of day, given the duration of a tick.
+func formatTimeOfDay(v float64, tickDuration time.Duration) string {
+	t := time.Duration(math.Round(v*float64(tickDuration)/float64(time.Second))) * time.Second
+	t %= 24 * time.Hour
+	if t < 0 {
+		t += 24 * time.Hour
+	}
+	h, m, s := int(t/time.Hour), int(t%time.Hour/time.Minute), int(t%time.Minute/time.Second)
+	if s == 0 {
+		return fmt.Sprintf("%02d:%02d", h, m)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+}
+
+// roundSignificant rounds to the given number of significant digits, to remove floating point noise.
+func roundSignificant(v float64, digits int) float64 {
+	if v == 0 {
+		return 0
+	}
+	f, err := strconv.ParseFloat(strconv.FormatFloat(v, 'g', digits, 64), 64)
+	if err != nil {
+		return v
+	}
+	return f
+}
+
+// formattedTicks replaces the labels of major ticks using a format function.
+type formattedTicks struct {
+	Ticker plot.Ticker
+	Format func(float64) string
+}
+
+// Ticks implements the plot.Ticker interface.
+func (t formattedTicks) Ticks(min, max float64) []plot.Tick {
+	ticks := t.Ticker.Ticks(min, max)
+	for i := range ticks {
+		if !ticks[i].IsMinor() {
+			ticks[i].Label = t.Format(ticks[i].Value)
+		}
+	}
+	return ticks
+}
+
+// logRange sanitizes an axis range for a logarithmic scale.
+// Non-positive minimums are replaced by a value three orders of magnitude below the maximum.
+func logRange(min, max float64) (float64, float64) {
+	if max <= 0 {
+		return 1, 10
+	}
+	if min <= 0 {
+		min = max / 1000
+	}
+	return min, max
+}
+
+// logScale is a logarithmic scale that clips non-positive values.
+type logScale struct{}
+
+// Normalize implements the plot.Normalizer interface.
+func (logScale) Normalize(min, max, x float64) float64 {
+	min, max = logRange(min, max)
+	x = math.Max(x, min)
+	return plot.LogScale{}.Normalize(min, max, x)
+}
+
+// logTicks are ticks for logarithmic scales that handle non-positive ranges.
+type logTicks struct{}
+
+// Ticks implements the plot.Ticker interface.
+func (logTicks) Ticks(min, max float64) []plot.Tick {
+	min, max = logRange(min, max)
+	return plot.LogTicks{Prec: -1}.Ticks(min, max)
+}
+
+// symLogScale is a symmetric logarithmic scale, linear in a range around zero.
+type symLogScale struct {
+	Threshold float64
+}
+
+// Normalize implements the plot.Normalizer interface.
+func (s symLogScale) Normalize(min, max, x float64) float64 {
+	lo, hi := symLog(min, s.Threshold), symLog(max, s.Threshold)
+	return (symLog(x, s.Threshold) - lo) / (hi - lo)
+}
+
+// symLog transforms a value to symmetric logarithmic space.
+func symLog(x, threshold float64) float64 {
+	return math.Copysign(math.Log10(1+math.Abs(x)/threshold), x)
+}
+
+// symLogTicks are ticks for symmetric logarithmic scales, at zero and powers of ten.
+type symLogTicks struct {
+	Threshold float64
+}
+
+// Ticks implements the plot.Ticker interface.
+func (t symLogTicks) Ticks(min, max float64) []plot.Tick {
+	ticks := []plot.Tick{}
+	add := func(v float64) {
+		if v >= min && v <= max {
+			ticks = append(ticks, plot.Tick{Value: v, Label: strconv.FormatFloat(v, 'g', -1, 64)})
+		}
+	}
+	lowest := math.Floor(math.Log10(t.Threshold))
+	highest := math.Ceil(math.Log10(math.Max(math.Abs(min), math.Abs(max))))
+	if math.IsInf(lowest, 0) || math.IsNaN(highest) || math.IsInf(highest, 0) {
+		// No ticks for non-finite ranges, as the default ticker does not terminate for them either.
+		return nil
+	}
+	for e := highest; e >= lowest; e-- {
+		add(-math.Pow(10, e))
+	}
+	add(0)
+	for e := lowest; e <= highest; e++ {
+		add(math.Pow(10, e))
+	}
+	if len(ticks) < 2 {
+		return plot.DefaultTicks{}.Ticks(min, max)
+	}
+	return ticks
+}
@@ -0,0 +1,81 @@
+package plot
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot"
+)
+
+func TestFormatSI(t *testing.T) {
+	assert.Equal(t, "0", formatSI(0))
+	assert.Equal(t, "1.5k", formatSI(1500))
+	assert.Equal(t, "-20M", formatSI(-2e7))
+	assert.Equal(t, "200m", formatSI(0.2))
+	assert.Equal(t, "12", formatSI(12))
+	assert.Equal(t, "3000P", formatSI(3e18))
+}
+
+func TestFormatPercent(t *testing.T) {
+	assert.Equal(t, "25%", formatPercent(0.25))
+	assert.Equal(t, "29%", formatPercent(0.29))
+	assert.Equal(t, "-150%", formatPercent(-1.5))
+}
+
+func TestFormatTimeOfDay(t *testing.T) {
+	assert.Equal(t, "06:30", formatTimeOfDay(390, time.Minute))
+	assert.Equal(t, "00:00:30", formatTimeOfDay(30, time.Second))
+	assert.Equal(t, "01:00", formatTimeOfDay(25, time.Hour))
+}
+
+func TestSetAxis(t *testing.T) {
+	p := plot.New()
+	p.X.Tick.Marker = removeLastTicks{}
+	setAxis(&p.X, Axis{Scale: LogScale, Format: SIFormat, Invert: true})
+
+	marker, ok := p.X.Tick.Marker.(removeLastTicks)
+	assert.True(t, ok)
+	assert.IsType(t, formattedTicks{}, marker.Ticker)
+	assert.IsType(t, plot.InvertedScale{}, p.X.Scale)
+
+	ticks := marker.Ticks(1, 1000)
+	assert.Equal(t, "1", ticks[0].Label)
+}
+
+func TestLogScale(t *testing.T) {
+	s := logScale{}
+	assert.InDelta(t, 0.5, s.Normalize(1, 100, 10), 1e-9)
+	// Non-positive values are clipped.
+	assert.InDelta(t, 0, s.Normalize(0, 1000, -5), 1e-9)
+	assert.InDelta(t, 1, s.Normalize(0, 1000, 1000), 1e-9)
+
+	ticks := logTicks{}.Ticks(0, 1000)
+	assert.Equal(t, 1.0, ticks[0].Value)
+}
+
+func TestSymLogScale(t *testing.T) {
+	s := symLogScale{Threshold: 1}
+	assert.InDelta(t, 0.5, s.Normalize(-100, 100, 0), 1e-9)
+	assert.InDelta(t, 1, s.Normalize(-100, 100, 100), 1e-9)
+	assert.Less(t, s.Normalize(-100, 100, 10), 0.8)
+
+	ticks := symLogTicks{Threshold: 1}.Ticks(-100, 1000)
+	values := []float64{}
+	for _, tick := range ticks {
+		values = append(values, tick.Value)
+	}
+	assert.Equal(t, []float64{-100, -10, -1, 0, 1, 10, 100, 1000}, values)
+
+	assert.Empty(t, symLogTicks{Threshold: 1}.Ticks(math.Inf(-1), math.Inf(1)))
+	assert.Empty(t, symLogTicks{Threshold: 1}.Ticks(math.NaN(), 1))
+	assert.Empty(t, symLogTicks{Threshold: 0}.Ticks(-100, 100))
+}
+
+func TestInvertNorm(t *testing.T) {
+	assert.InDelta(t, 25, invertNorm(plot.LinearScale{}, [2]float64{0, 100}, 0.25), 1e-9)
+	assert.InDelta(t, 75, invertNorm(plot.InvertedScale{Normalizer: plot.LinearScale{}}, [2]float64{0, 100}, 0.25), 1e-6)
+	assert.InDelta(t, 10, invertNorm(logScale{}, [2]float64{1, 100}, 0.5), 1e-6)
+	assert.InDelta(t, 0, invertNorm(symLogScale{Threshold: 1}, [2]float64{-100, 100}, 0.5), 1e-6)
+}
@@ -0,0 +1,202 @@
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Default opacity of bands.
+const defaultBandOpacity = 0.25
+
+// Band is a shaded area around a line, between two columns, like an uncertainty range.
+//
+// The band is drawn in the color of its central line, and shares its legend entry.
+// Bounds are given either by Lower and Upper, or by a symmetric Spread around the line.
+// Bound columns are excluded from the columns shown by default.
+type Band struct {
+	Line    string  // Column of the central line. Must be among the shown columns, with at most one band per line.
+	Lower   string  // Column of the lower bound, like a 5% quantile.
+	Upper   string  // Column of the upper bound, like a 95% quantile.
+	Spread  string  // Column of a symmetric spread around the line, like a standard deviation. Alternative to Lower and Upper.
+	Opacity float64 // Opacity of the band. Optional, default 0.25.
+}
+
+// bandColumns are the column indices of a band. Unused columns are -1.
+type bandColumns struct {
+	Line    int
+	Lower   int
+	Upper   int
+	Spread  int
+	Opacity float64
+}
+
+// resolveBands finds the columns of bands by name.
+// Panics if a column is not found, or if the bounds are not fully specified.
+func resolveBands(headers []string, bands []Band) []bandColumns {
+	cols := make([]bandColumns, len(bands))
+	column := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		idx, ok := find(headers, name)
+		if !ok {
+			panic(fmt.Sprintf("band column '%s' not found", name))
+		}
+		return idx
+	}
+	for i, b := range bands {
+		if b.Line == "" {
+			panic("band requires a line column")
+		}
+		if (b.Spread == "") == (b.Lower == "" || b.Upper == "") {
+			panic(fmt.Sprintf("band for '%s' requires either lower and upper, or spread column", b.Line))
+		}
+		opacity := b.Opacity
+		if opacity <= 0 {
+			opacity = defaultBandOpacity
+		}
+		cols[i] = bandColumns{
+			Line:    column(b.Line),
+			Lower:   column(b.Lower),
+			Upper:   column(b.Upper),
+			Spread:  column(b.Spread),
+			Opacity: opacity,
+		}
+	}
+	return cols
+}
+
+// isBandBound checks whether a column is used as a bound by any of the bands.
+func isBandBound(bands []bandColumns, idx int) bool {
+	for _, b := range bands {
+		if idx == b.Lower || idx == b.Upper || idx == b.Spread {
+			return true
+		}
+	}
+	return false
+}
+
+// bandsByLine assigns bands to the shown columns, by their central line.
+// Panics if the line of a band is not among the shown columns, or if a line has more than one band.
+func bandsByLine(headers []string, indices []int, bands []bandColumns) []int {
+	byLine := make([]int, len(indices))
+	for i := range byLine {
+		byLine[i] = -1
+	}
+	for i, b := range bands {
+		pos, ok := find(indices, b.Line)
+		if !ok {
+			panic(fmt.Sprintf("band line column '%s' not found", headers[b.Line]))
+		}
+		if byLine[pos] >= 0 {
+			panic(fmt.Sprintf("duplicate band for line column '%s'", headers[b.Line]))
+		}
+		byLine[pos] = i
+	}
+	return byLine
+}
+
+// bounds calculates the lower and upper bounds of the band for a number of rows,
+// given functions for the X value of a row, and for the value of a column in a row.
+// Appends to lower[:0] and upper[:0].
+func (b *bandColumns) bounds(rows int, x func(row int) float64, value func(row, col int) float64,
+	lower, upper plotter.XYs) (plotter.XYs, plotter.XYs) {
+	lower, upper = lower[:0], upper[:0]
+	for r := range rows {
+		xv := x(r)
+		if b.Spread >= 0 {
+			line, spread := value(r, b.Line), value(r, b.Spread)
+			lower = append(lower, plotter.XY{X: xv, Y: line - spread})
+			upper = append(upper, plotter.XY{X: xv, Y: line + spread})
+		} else {
+			lower = append(lower, plotter.XY{X: xv, Y: value(r, b.Lower)})
+			upper = append(upper, plotter.XY{X: xv, Y: value(r, b.Upper)})
+		}
+	}
+	return lower, upper
+}
+
+// bandColor returns the fill color of a band, from the color of its line.
+func bandColor(c color.Color, opacity float64) color.Color {
+	col := color.NRGBAModel.Convert(c).(color.NRGBA)
+	col.A = uint8(math.Round(float64(col.A) * math.Min(opacity, 1)))
+	return col
+}
+
+// bandPlotter is a plotter for a shaded band between lower and upper bounds.
+// Rows with NaN bounds are left out, splitting the band into segments.
+type bandPlotter struct {
+	Lower plotter.XYs
+	Upper plotter.XYs
+	Color color.Color
+}
+
+// newBandPlotter creates a band plotter, copying the data.
+func newBandPlotter(lower, upper plotter.XYs, c color.Color) *bandPlotter {
+	return &bandPlotter{
+		Lower: append(plotter.XYs(nil), lower...),
+		Upper: append(plotter.XYs(nil), upper...),
+		Color: c,
+	}
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (b *bandPlotter) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	pts := []vg.Point{}
+	flush := func(start, end int) {
+		if end-start < 2 {
+			return
+		}
+		pts = pts[:0]
+		for i := start; i < end; i++ {
+			pts = append(pts, vg.Point{X: trX(b.Upper[i].X), Y: trY(b.Upper[i].Y)})
+		}
+		for i := end - 1; i >= start; i-- {
+			pts = append(pts, vg.Point{X: trX(b.Lower[i].X), Y: trY(b.Lower[i].Y)})
+		}
+		c.FillPolygon(b.Color, c.ClipPolygonXY(pts))
+	}
+
+	start := 0
+	for i := range b.Lower {
+		if math.IsNaN(b.Lower[i].Y) || math.IsNaN(b.Upper[i].Y) {
+			flush(start, i)
+			start = i + 1
+		}
+	}
+	flush(start, len(b.Lower))
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+func (b *bandPlotter) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, ymin = math.Inf(1), math.Inf(1)
+	xmax, ymax = math.Inf(-1), math.Inf(-1)
+	for _, s := range []plotter.XYs{b.Lower, b.Upper} {
+		for _, pt := range s {
+			if math.IsNaN(pt.Y) {
+				continue
+			}
+			xmin, xmax = math.Min(xmin, pt.X), math.Max(xmax, pt.X)
+			ymin, ymax = math.Min(ymin, pt.Y), math.Max(ymax, pt.Y)
+		}
+	}
+	return xmin, xmax, ymin, ymax
+}
+
+// Thumbnail implements the Thumbnail method of the plot.Thumbnailer interface.
+func (b *bandPlotter) Thumbnail(c *draw.Canvas) {
+	pts := []vg.Point{
+		{X: c.Min.X, Y: c.Min.Y},
+		{X: c.Max.X, Y: c.Min.Y},
+		{X: c.Max.X, Y: c.Max.Y},
+		{X: c.Min.X, Y: c.Max.Y},
+	}
+	c.FillPolygon(b.Color, c.ClipPolygonY(pts))
+}
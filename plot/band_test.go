@@ -0,0 +1,77 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestResolveBands(t *testing.T) {
+	headers := []string{"X", "Mean", "SD", "Q05", "Q95"}
+	bands := resolveBands(headers, []Band{
+		{Line: "Mean", Spread: "SD"},
+		{Line: "Mean", Lower: "Q05", Upper: "Q95", Opacity: 0.5},
+	})
+	assert.Equal(t, []bandColumns{
+		{Line: 1, Lower: -1, Upper: -1, Spread: 2, Opacity: defaultBandOpacity},
+		{Line: 1, Lower: 3, Upper: 4, Spread: -1, Opacity: 0.5},
+	}, bands)
+
+	assert.False(t, isBandBound(bands, 0))
+	assert.False(t, isBandBound(bands, 1))
+	assert.True(t, isBandBound(bands, 2))
+	assert.True(t, isBandBound(bands, 4))
+
+	assert.Equal(t, []int{-1, 0}, bandsByLine(headers, []int{0, 1}, bands[:1]))
+	assert.Panics(t, func() { bandsByLine(headers, []int{0}, bands) })
+	assert.Panics(t, func() { bandsByLine(headers, []int{0, 1}, bands) })
+
+	assert.Panics(t, func() { resolveBands(headers, []Band{{Line: "Mean", Spread: "Var"}}) })
+	assert.Panics(t, func() { resolveBands(headers, []Band{{Line: "Mean", Lower: "Q05"}}) })
+	assert.Panics(t, func() { resolveBands(headers, []Band{{Line: "Mean", Lower: "Q05", Upper: "Q95", Spread: "SD"}}) })
+	assert.Panics(t, func() { resolveBands(headers, []Band{{Spread: "SD"}}) })
+}
+
+func TestBandBounds(t *testing.T) {
+	data := [][]float64{
+		{0, 10, 1, 8, 12},
+		{1, 20, 2, 17, 23},
+	}
+	x := func(row int) float64 { return data[row][0] }
+	value := func(row, col int) float64 { return data[row][col] }
+
+	b := bandColumns{Line: 1, Lower: -1, Upper: -1, Spread: 2}
+	lower, upper := b.bounds(2, x, value, nil, nil)
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 9}, {X: 1, Y: 18}}, lower)
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 11}, {X: 1, Y: 22}}, upper)
+
+	b = bandColumns{Line: 1, Lower: 3, Upper: 4, Spread: -1}
+	lower, upper = b.bounds(2, x, value, lower, upper)
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 8}, {X: 1, Y: 17}}, lower)
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 12}, {X: 1, Y: 23}}, upper)
+}
+
+func TestBandPlotter(t *testing.T) {
+	assert.Equal(t, color.NRGBA{R: 255, A: 64}, bandColor(color.RGBA{R: 255, A: 255}, 0.25))
+
+	lower := plotter.XYs{{X: 0, Y: 0}, {X: 1, Y: math.NaN()}, {X: 2, Y: 1}, {X: 3, Y: 2}}
+	upper := plotter.XYs{{X: 0, Y: 1}, {X: 1, Y: 5}, {X: 2, Y: 3}, {X: 3, Y: 4}}
+	band := newBandPlotter(lower, upper, color.Black)
+	lower[0].Y = 100
+	assert.Equal(t, 0.0, band.Lower[0].Y)
+
+	xmin, xmax, ymin, ymax := band.DataRange()
+	assert.Equal(t, []float64{0, 3, 0, 5}, []float64{xmin, xmax, ymin, ymax})
+
+	p := plot.New()
+	p.Add(band)
+	p.Legend.Add("band", band)
+	path := filepath.Join(t.TempDir(), "band.svg")
+	assert.Nil(t, saveFigure(&figure{plot: p}, path, 4*vg.Inch, 3*vg.Inch))
+}
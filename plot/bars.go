@@ -4,112 +4,238 @@ import (
 	"fmt"
 	"image/color"
 
-	pixel "github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/mlange-42/ark-tools/observer"
 	"github.com/mlange-42/ark/ecs"
 	"gonum.org/v1/plot"
-	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
 	"gonum.org/v1/plot/vg/draw"
-	"gonum.org/v1/plot/vg/vgimg"
 )
 
 // Bars plot drawer.
 //
 // Creates a bar per column of the observer.
+// With further observers, bars of the same column are grouped side by side, or stacked.
+// Bars can be drawn horizontally, with error bars from companion columns and with value labels.
 type Bars struct {
-	Observer observer.Row // Observer providing a data series for bars.
-	Columns  []string     // Columns to show, by name. Optional, default all.
-	YLim     [2]float64   // Y axis limits. Optional, default auto.
-	Labels   Labels       // Labels for plot and axes. Optional.
-
-	indices []int
-	headers []string
-	series  plotter.Values
-	scale   float64
+	Observer    observer.Row   // Observer providing a data series for bars.
+	Observers   []observer.Row // Further observers for grouped or stacked bars, with the columns of Observer. Optional.
+	Names       []string       // Legend names of the series of Observer and Observers. Optional, default no legend.
+	Columns     []string       // Columns to show, by name. Optional, default all but error columns.
+	Errors      []string       // Columns with symmetric errors for error bars, one per shown column. Empty names for none. Optional.
+	Stacked     bool           // Stacks the bars of multiple observers instead of grouping them. Optional.
+	Horizontal  bool           // Draws horizontal bars, with the first column at the bottom. Optional.
+	Colors      []color.Color  // Colors per column, cycled. Only used without further observers. Optional, default a style color per observer.
+	ValueLabels bool           // Shows values as labels on top of bars. Optional.
+	YLim        [2]float64     // Value axis limits. Optional, default auto.
+	Labels      Labels         // Labels for plot and axes. Optional.
+	Style       *Style         // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	YAxis       Axis           // Value axis configuration (scale, tick format, grid, ...). Applies to the X axis for horizontal bars. Optional.
+	Async       bool           // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath    string         // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+	ExportPath  string         // File for exporting the data with Ctrl+E, format by extension. Optional, default time-stamped CSV.
+
+	observers  []observer.Row
+	indices    [][]int
+	errIndices [][]int
+	headers    []string
+	values     [][]float64
+	errors     [][]float64
+	scale      float64
+	style      *Style
+	renderer   plotRenderer
 }
 
 // Initialize the drawer.
 func (b *Bars) Initialize(w *ecs.World, _ *opengl.Window) {
-	b.Observer.Initialize(w)
+	b.observers = append([]observer.Row{b.Observer}, b.Observers...)
+	for _, obs := range b.observers {
+		obs.Initialize(w)
+	}
+	if len(b.Names) > 0 && len(b.Names) != len(b.observers) {
+		panic("bars require one name per observer")
+	}
 
 	headers := b.Observer.Header()
-
 	if len(b.Columns) == 0 {
-		b.indices = make([]int, len(headers))
-		for i := 0; i < len(b.indices); i++ {
-			b.indices[i] = i
+		b.headers = make([]string, 0, len(headers))
+		for _, name := range headers {
+			if _, isErr := find(b.Errors, name); !isErr {
+				b.headers = append(b.headers, name)
+			}
 		}
 	} else {
-		b.indices = make([]int, len(b.Columns))
+		b.headers = append([]string(nil), b.Columns...)
+	}
+	if len(b.Errors) > 0 && len(b.Errors) != len(b.headers) {
+		panic("bars require one error column per shown column")
+	}
+
+	b.indices = make([][]int, len(b.observers))
+	b.errIndices = make([][]int, len(b.observers))
+	b.values = make([][]float64, len(b.observers))
+	b.errors = make([][]float64, len(b.observers))
+	for i, obs := range b.observers {
+		headers := obs.Header()
+		b.indices[i] = make([]int, len(b.headers))
 		var ok bool
-		for i := 0; i < len(b.indices); i++ {
-			b.indices[i], ok = find(headers, b.Columns[i])
+		for j, name := range b.headers {
+			b.indices[i][j], ok = find(headers, name)
 			if !ok {
-				panic(fmt.Sprintf("column '%s' not found", b.Columns[i]))
+				panic(fmt.Sprintf("column '%s' not found", name))
 			}
 		}
-	}
+		b.values[i] = make([]float64, len(b.headers))
 
-	b.series = make([]float64, len(b.indices))
-	b.headers = make([]string, len(b.indices))
-	for i, idx := range b.indices {
-		b.headers[i] = headers[idx]
+		if len(b.Errors) == 0 {
+			continue
+		}
+		b.errIndices[i] = make([]int, len(b.Errors))
+		for j, name := range b.Errors {
+			b.errIndices[i][j] = -1
+			if name == "" {
+				continue
+			}
+			b.errIndices[i][j], ok = find(headers, name)
+			if !ok {
+				panic(fmt.Sprintf("error column '%s' not found", name))
+			}
+		}
+		b.errors[i] = make([]float64, len(b.Errors))
 	}
 
 	b.scale = calcScaleCorrection()
-
+	b.style = b.Style.resolve()
+	b.renderer = plotRenderer{}
 }
 
 // Update the drawer.
 func (b *Bars) Update(w *ecs.World) {
-	b.Observer.Update(w)
+	for _, obs := range b.observers {
+		obs.Update(w)
+	}
+	b.renderer.Invalidate()
 }
 
 // UpdateInputs handles input events of the previous frame update.
-func (b *Bars) UpdateInputs(_ *ecs.World, _ *opengl.Window) {}
+func (b *Bars) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	b.renderer.HandleInputs(win, b.scale)
+	if savePressed(win) {
+		saveWindow(win, b.SavePath, b.scale, b.SaveAs)
+	}
+	if exportPressed(win) {
+		exportWindow(b.ExportPath, b.ExportData)
+	}
+}
 
 // Draw the drawer.
 func (b *Bars) Draw(w *ecs.World, win *opengl.Window) {
-	width := win.Canvas().Bounds().W()
-	height := win.Canvas().Bounds().H()
+	b.renderer.Draw(win, b.scale, b.Async, func() *figure {
+		b.updateData(w)
+		return b.buildFigure()
+	})
+}
 
-	b.updateData(w)
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (b *Bars) SaveAs(path string, width, height vg.Length) error {
+	return b.renderer.Save(path, width, height)
+}
 
-	c := vgimg.New(vg.Points(width*b.scale)-10, vg.Points(height*b.scale)-10)
+// ExportData writes the current data of the plot to a file, with column headers from the observer.
+// Writes a row per observer, with the shown columns followed by the error columns.
+// The file format is selected by the extension. Supported are csv and json.
+func (b *Bars) ExportData(path string) error {
+	columns := make([]dataColumn, 0, len(b.headers)+len(b.Errors))
+	for i, name := range b.headers {
+		col := dataColumn{Name: name, Values: make([]float64, len(b.values))}
+		for s, values := range b.values {
+			col.Values[s] = values[i]
+		}
+		columns = append(columns, col)
+	}
+	for i, name := range b.Errors {
+		if name == "" {
+			continue
+		}
+		col := dataColumn{Name: name, Values: make([]float64, len(b.errors))}
+		for s, errors := range b.errors {
+			col.Values[s] = errors[i]
+		}
+		columns = append(columns, col)
+	}
+	return exportData(path, columns)
+}
 
+func (b *Bars) buildFigure() *figure {
 	p := plot.New()
-	setLabels(p, b.Labels)
+	setLabels(p, b.Labels, b.style)
+
+	valueAxis := &p.Y
+	if b.Horizontal {
+		valueAxis = &p.X
+	}
+	setAxis(valueAxis, b.YAxis)
+	addGrid(p, b.Horizontal && b.YAxis.Grid, !b.Horizontal && b.YAxis.Grid, b.style)
 
 	if b.YLim[0] != 0 || b.YLim[1] != 0 {
-		p.Y.Min = b.YLim[0]
-		p.Y.Max = b.YLim[1]
+		valueAxis.Min = b.YLim[0]
+		valueAxis.Max = b.YLim[1]
 	}
 
-	bw := 0.5 * (width - 50) / float64(len(b.series))
-	bars, err := plotter.NewBarChart(b.series, vg.Points(bw))
-	if err != nil {
-		panic(err)
+	chart := &barChart{
+		Values:     make([][]float64, len(b.values)),
+		Colors:     make([]color.Color, len(b.values)),
+		BarColors:  b.Colors,
+		Stacked:    b.Stacked,
+		Horizontal: b.Horizontal,
+		LineStyle:  draw.LineStyle{Color: b.style.Foreground, Width: b.style.LineWidth},
+		ErrorStyle: draw.LineStyle{Color: b.style.Foreground, Width: b.style.LineWidth},
+		TextStyle:  valueAxis.Tick.Label,
+		Labels:     b.ValueLabels,
+		Format:     b.YAxis.formatter(),
 	}
-	bars.Color = defaultColors[0]
-	p.Add(bars)
-	p.NominalX(b.headers...)
+	for s, values := range b.values {
+		chart.Values[s] = append([]float64(nil), values...)
+		chart.Colors[s] = b.style.color(s)
+	}
+	if len(b.Errors) > 0 {
+		chart.Errors = make([][]float64, len(b.errors))
+		for s, errors := range b.errors {
+			chart.Errors[s] = append([]float64(nil), errors...)
+		}
+	}
+	p.Add(chart)
 
-	win.Clear(color.White)
-	p.Draw(draw.New(c))
+	if len(b.headers) > 0 {
+		if b.Horizontal {
+			p.NominalY(b.headers...)
+		} else {
+			p.NominalX(b.headers...)
+		}
+	}
 
-	img := c.Image()
-	picture := pixel.PictureDataFromImage(img)
+	if len(b.Names) > 0 {
+		p.Legend = newLegend(b.style)
+		for s, name := range b.Names {
+			p.Legend.Add(name, barThumbnail{Color: chart.color(s, 0), LineStyle: chart.LineStyle})
+		}
+	}
 
-	sprite := pixel.NewSprite(picture, picture.Bounds())
-	sprite.Draw(win, pixel.IM.Moved(pixel.V(picture.Rect.W()/2.0+5, picture.Rect.H()/2.0+5)))
+	return &figure{plot: p, series: chart.series(b.Names, b.headers)}
 }
 
 func (b *Bars) updateData(w *ecs.World) {
-	values := b.Observer.Values(w)
-
-	for i, idx := range b.indices {
-		b.series[i] = values[idx]
+	for s, obs := range b.observers {
+		values := obs.Values(w)
+		for i, idx := range b.indices[s] {
+			b.values[s][i] = values[idx]
+		}
+		for i, idx := range b.errIndices[s] {
+			b.errors[s][i] = 0
+			if idx >= 0 {
+				b.errors[s][i] = values[idx]
+			}
+		}
 	}
 }
@@ -0,0 +1,266 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Width of a group of bars, in units of the distance between categories.
+const barGroupWidth = 0.8
+
+// barChart is a plotter for bars of multiple series per category,
+// either grouped side by side or stacked.
+//
+// Categories are placed at integer positions along the category axis, i.e. X for vertical
+// and Y for horizontal bars. Bar widths are in data units, so that bars follow zoom.
+// When stacked, positive and negative values are stacked separately, starting from zero.
+type barChart struct {
+	Values     [][]float64          // Values per series and category. NaN values are left out.
+	Errors     [][]float64          // Symmetric errors per series and category. Optional, may contain nil entries.
+	Colors     []color.Color        // Colors per series.
+	BarColors  []color.Color        // Colors per category, cycled. Optional, overrides Colors for a single series.
+	Stacked    bool                 // Stacks series instead of grouping them.
+	Horizontal bool                 // Draws horizontal bars.
+	LineStyle  draw.LineStyle       // Outline of bars.
+	ErrorStyle draw.LineStyle       // Style of error bars.
+	TextStyle  text.Style           // Style of value labels.
+	Labels     bool                 // Shows values as labels at the end of bars.
+	Format     func(float64) string // Format of value labels. Optional.
+}
+
+// barExtent is the extent of a bar, along the category and the value axis.
+type barExtent struct {
+	Min, Max  float64 // Extent along the category axis.
+	Base, Top float64 // Extent along the value axis.
+	Value     float64 // Value of the bar.
+}
+
+// extents calculates the extents of all bars, per series and category.
+// Extents of NaN values have NaN values.
+func (b *barChart) extents() [][]barExtent {
+	extents := make([][]barExtent, len(b.Values))
+	width := barGroupWidth
+	if !b.Stacked && len(b.Values) > 0 {
+		width /= float64(len(b.Values))
+	}
+	var pos, neg []float64
+	if len(b.Values) > 0 {
+		pos = make([]float64, len(b.Values[0]))
+		neg = make([]float64, len(b.Values[0]))
+	}
+	for s, values := range b.Values {
+		extents[s] = make([]barExtent, len(values))
+		for c, v := range values {
+			start := float64(c) - barGroupWidth/2
+			if !b.Stacked {
+				start += float64(s) * width
+			}
+			ext := barExtent{Min: start, Max: start + width, Value: v, Top: v}
+			switch {
+			case math.IsNaN(v):
+				ext.Base = math.NaN()
+			case b.Stacked && v >= 0:
+				ext.Base, ext.Top = pos[c], pos[c]+v
+				pos[c] = ext.Top
+			case b.Stacked:
+				ext.Base, ext.Top = neg[c], neg[c]+v
+				neg[c] = ext.Top
+			}
+			extents[s][c] = ext
+		}
+	}
+	return extents
+}
+
+// error returns the error of a bar, or zero if there is none.
+func (b *barChart) error(s, c int) float64 {
+	if s >= len(b.Errors) || b.Errors[s] == nil {
+		return 0
+	}
+	return b.Errors[s][c]
+}
+
+// color returns the fill color of a bar.
+// Colors per category are only used for a single series, so that multiple series stay distinguishable.
+func (b *barChart) color(s, c int) color.Color {
+	if len(b.BarColors) > 0 && len(b.Values) == 1 {
+		return b.BarColors[c%len(b.BarColors)]
+	}
+	return b.Colors[s%len(b.Colors)]
+}
+
+// format a value label.
+func (b *barChart) format(v float64) string {
+	if b.Format != nil {
+		return b.Format(v)
+	}
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (b *barChart) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	// Converts category and value coordinates to a canvas point.
+	point := func(cat, val float64) vg.Point {
+		if b.Horizontal {
+			return vg.Point{X: trX(val), Y: trY(cat)}
+		}
+		return vg.Point{X: trX(cat), Y: trY(val)}
+	}
+
+	extents := b.extents()
+	for s, bars := range extents {
+		for cat, e := range bars {
+			if math.IsNaN(e.Value) {
+				continue
+			}
+			pts := []vg.Point{point(e.Min, e.Base), point(e.Max, e.Base), point(e.Max, e.Top), point(e.Min, e.Top)}
+			c.FillPolygon(b.color(s, cat), c.ClipPolygonXY(pts))
+			c.StrokeLines(b.LineStyle, c.ClipLinesXY(append(pts, pts[0]))...)
+		}
+	}
+
+	// Error bars and labels in a second pass, so that they are not covered by stacked bars.
+	for s, bars := range extents {
+		for cat, e := range bars {
+			if math.IsNaN(e.Value) {
+				continue
+			}
+			center := (e.Min + e.Max) / 2
+			err := b.error(s, cat)
+			if err > 0 {
+				whisker := (e.Max - e.Min) / 4
+				lo, hi := e.Top-err, e.Top+err
+				c.StrokeLines(b.ErrorStyle, c.ClipLinesXY(
+					[]vg.Point{point(center, lo), point(center, hi)},
+					[]vg.Point{point(center-whisker, lo), point(center+whisker, lo)},
+					[]vg.Point{point(center-whisker, hi), point(center+whisker, hi)},
+				)...)
+			}
+			if b.Labels {
+				b.drawLabel(c, point, e, center, err)
+			}
+		}
+	}
+}
+
+// drawLabel draws the value label of a bar, in the middle of stacked bars,
+// and at the end of grouped bars, beyond the error bar.
+func (b *barChart) drawLabel(c draw.Canvas, point func(cat, val float64) vg.Point, e barExtent, center, err float64) {
+	sty := b.TextStyle
+	gap := vg.Points(3)
+	if b.Stacked {
+		sty.XAlign, sty.YAlign = draw.XCenter, draw.YCenter
+		pt := point(center, (e.Base+e.Top)/2)
+		if c.Contains(pt) {
+			c.FillText(sty, pt, b.format(e.Value))
+		}
+		return
+	}
+	negative := e.Value < 0
+	end := e.Top + err
+	if negative {
+		end = e.Top - err
+	}
+	pt := point(center, end)
+	if b.Horizontal {
+		sty.XAlign, sty.YAlign = draw.XLeft, draw.YCenter
+		pt.X += gap
+		if negative {
+			sty.XAlign = draw.XRight
+			pt.X -= 2 * gap
+		}
+	} else {
+		sty.XAlign, sty.YAlign = draw.XCenter, draw.YBottom
+		pt.Y += gap
+		if negative {
+			sty.YAlign = draw.YTop
+			pt.Y -= 2 * gap
+		}
+	}
+	if c.Contains(point(center, e.Top)) {
+		c.FillText(sty, pt, b.format(e.Value))
+	}
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+// Includes zero, error bars and some room for value labels.
+func (b *barChart) DataRange() (xmin, xmax, ymin, ymax float64) {
+	lo, hi := 0.0, 0.0
+	categories := 0
+	for s, bars := range b.extents() {
+		categories = max(categories, len(bars))
+		for cat, e := range bars {
+			if math.IsNaN(e.Value) {
+				continue
+			}
+			err := b.error(s, cat)
+			lo = math.Min(lo, math.Min(e.Base, e.Top-err))
+			hi = math.Max(hi, math.Max(e.Base, e.Top+err))
+		}
+	}
+	if b.Labels && !b.Stacked {
+		pad := 0.08 * (hi - lo)
+		if lo < 0 {
+			lo -= pad
+		}
+		if hi > 0 {
+			hi += pad
+		}
+	}
+	cmin, cmax := -0.5, float64(categories)-0.5
+	if b.Horizontal {
+		return lo, hi, cmin, cmax
+	}
+	return cmin, cmax, lo, hi
+}
+
+// series returns a series per bar for the crosshair, named after its category and series,
+// with a point at the end of the bar and its original value for display.
+// Series names are optional.
+func (b *barChart) series(names, categories []string) []namedSeries {
+	series := []namedSeries{}
+	for s, bars := range b.extents() {
+		for cat, e := range bars {
+			if math.IsNaN(e.Value) {
+				continue
+			}
+			name := categories[cat]
+			if s < len(names) {
+				name = names[s] + " " + name
+			}
+			center := (e.Min + e.Max) / 2
+			xy, value := plotter.XY{X: center, Y: e.Top}, plotter.XY{X: center, Y: e.Value}
+			if b.Horizontal {
+				xy, value = plotter.XY{X: e.Top, Y: center}, plotter.XY{X: e.Value, Y: center}
+			}
+			series = append(series, namedSeries{Name: name, XYs: plotter.XYs{xy}, Values: plotter.XYs{value}})
+		}
+	}
+	return series
+}
+
+// barThumbnail is a legend entry for a series of bars.
+type barThumbnail struct {
+	Color     color.Color
+	LineStyle draw.LineStyle
+}
+
+// Thumbnail implements the Thumbnail method of the plot.Thumbnailer interface.
+func (t barThumbnail) Thumbnail(c *draw.Canvas) {
+	pts := []vg.Point{
+		{X: c.Min.X, Y: c.Min.Y},
+		{X: c.Max.X, Y: c.Min.Y},
+		{X: c.Max.X, Y: c.Max.Y},
+		{X: c.Min.X, Y: c.Max.Y},
+	}
+	c.FillPolygon(t.Color, c.ClipPolygonY(pts))
+	c.StrokeLines(t.LineStyle, append(pts, pts[0]))
+}
@@ -0,0 +1,78 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestBarChartExtents(t *testing.T) {
+	chart := barChart{Values: [][]float64{{1, math.NaN()}, {2, -1}}}
+	ext := chart.extents()
+	assert.Equal(t, barExtent{Min: -0.4, Max: 0, Base: 0, Top: 1, Value: 1}, ext[0][0])
+	assert.True(t, math.IsNaN(ext[0][1].Base))
+	assert.InDelta(t, 0, ext[1][0].Min, 1e-12)
+	assert.InDelta(t, 0.4, ext[1][0].Max, 1e-12)
+
+	xmin, xmax, ymin, ymax := chart.DataRange()
+	assert.Equal(t, []float64{-0.5, 1.5, -1, 2}, []float64{xmin, xmax, ymin, ymax})
+
+	chart.Stacked = true
+	chart.Values = [][]float64{{1, -1}, {2, -2}, {3, 1}}
+	ext = chart.extents()
+	assert.Equal(t, barExtent{Min: -0.4, Max: 0.4, Base: 1, Top: 3, Value: 2}, ext[1][0])
+	assert.Equal(t, barExtent{Min: 0.6, Max: 1.4, Base: -1, Top: -3, Value: -2}, ext[1][1])
+	assert.Equal(t, barExtent{Min: 0.6, Max: 1.4, Base: 0, Top: 1, Value: 1}, ext[2][1])
+
+	chart.Horizontal = true
+	chart.Errors = [][]float64{nil, nil, {0.5, 0}}
+	xmin, xmax, ymin, ymax = chart.DataRange()
+	assert.Equal(t, []float64{-3, 6.5, -0.5, 1.5}, []float64{xmin, xmax, ymin, ymax})
+}
+
+func TestBarChartSeries(t *testing.T) {
+	chart := barChart{Values: [][]float64{{1, math.NaN()}}, Horizontal: true}
+	series := chart.series(nil, []string{"A", "B"})
+	assert.Equal(t, []namedSeries{
+		{Name: "A", XYs: plotter.XYs{{X: 1, Y: 0}}, Values: plotter.XYs{{X: 1, Y: 0}}},
+	}, series)
+
+	chart = barChart{Values: [][]float64{{1}, {2}}, Stacked: true}
+	series = chart.series([]string{"S1", "S2"}, []string{"A"})
+	assert.Equal(t, "S2 A", series[1].Name)
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 3}}, series[1].XYs)
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 2}}, series[1].Values)
+}
+
+func TestBarChartPlot(t *testing.T) {
+	p := plot.New()
+	chart := &barChart{
+		Values:    [][]float64{{1, -2, math.NaN()}, {2, 1, 3}},
+		Errors:    [][]float64{{0.5, 0.5, 0.5}, nil},
+		Colors:    []color.Color{color.Black},
+		BarColors: []color.Color{color.Black, color.White},
+		Labels:    true,
+		TextStyle: p.Y.Tick.Label,
+		Format:    formatSI,
+	}
+	assert.Equal(t, color.Black, chart.color(0, 1))
+	single := barChart{Values: chart.Values[:1], Colors: chart.Colors, BarColors: chart.BarColors}
+	assert.Equal(t, color.White, single.color(0, 1))
+	assert.Equal(t, "1.5k", chart.format(1500))
+	chart.Format = nil
+	assert.Equal(t, "0.3333", chart.format(1.0/3))
+
+	p.Add(chart)
+	p.Legend.Add("bars", barThumbnail{Color: color.Black})
+	path := filepath.Join(t.TempDir(), "bars.svg")
+	assert.Nil(t, saveFigure(&figure{plot: p}, path, 4*vg.Inch, 3*vg.Inch))
+
+	chart.Stacked, chart.Horizontal = true, true
+	assert.Nil(t, saveFigure(&figure{plot: p}, path, 4*vg.Inch, 3*vg.Inch))
+}
@@ -1,11 +1,16 @@
 package plot_test
 
 import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/mlange-42/ark-pixel/plot"
 	"github.com/mlange-42/ark-pixel/window"
 	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/observer"
 	"github.com/mlange-42/ark-tools/system"
 	"github.com/stretchr/testify/assert"
 )
@@ -56,6 +61,66 @@ func TestBars_Columns(t *testing.T) {
 	app.Run()
 }
 
+func TestBars_Grouped(t *testing.T) {
+	bars := plot.Bars{
+		Observer:    &RowObserver{},
+		Observers:   []observer.Row{&RowObserver{}},
+		Names:       []string{"X", "Y"},
+		Errors:      []string{"C", ""},
+		ValueLabels: true,
+		YAxis:       plot.Axis{Grid: true, Format: plot.SIFormat},
+	}
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&bars))
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "bars.csv")
+	assert.Nil(t, bars.ExportData(path))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "A,B,C", lines[0])
+}
+
+func TestBars_StackedHorizontal(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Bars{
+			Observer:    &RowObserver{},
+			Observers:   []observer.Row{&RowObserver{}, &RowObserver{}},
+			Stacked:     true,
+			Horizontal:  true,
+			ValueLabels: true,
+			Colors:      []color.Color{color.RGBA{R: 200, A: 255}, color.RGBA{B: 200, A: 255}},
+		}))
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestBars_Panic(t *testing.T) {
+	for _, bars := range []plot.Bars{
+		{Observer: &RowObserver{}, Names: []string{"X", "Y"}},
+		{Observer: &RowObserver{}, Errors: []string{"C"}},
+		{Observer: &RowObserver{}, Columns: []string{"A"}, Errors: []string{"F"}},
+	} {
+		app := app.New()
+		app.TPS = 300
+		app.AddUISystem((&window.Window{}).With(&bars))
+		app.AddSystem(&system.FixedTermination{
+			Steps: 100,
+		})
+		assert.Panics(t, app.Run)
+	}
+}
+
 func TestBars_PanicColumns(t *testing.T) {
 	app := app.New()
 	app.TPS = 300
@@ -0,0 +1,121 @@
+package plot
+
+import (
+	"math"
+	"sort"
+)
+
+// Binning is a rule for the automatic number of histogram bins.
+type Binning uint8
+
+const (
+	// SturgesBinning uses Sturges' rule, with ceil(log2(n)) + 1 bins for n values.
+	// Suitable for roughly normal distributed data.
+	SturgesBinning Binning = iota
+	// FreedmanDiaconisBinning uses the Freedman–Diaconis rule, with a bin width of 2 IQR / cbrt(n).
+	// Robust to outliers and suitable for skewed data.
+	FreedmanDiaconisBinning
+)
+
+// HistogramNorm is the normalization of histogram bin heights.
+type HistogramNorm uint8
+
+const (
+	// HistogramCount shows the number of values per bin.
+	HistogramCount HistogramNorm = iota
+	// HistogramDensity shows the probability density, so that the total area of the bins is 1.
+	HistogramDensity
+	// HistogramCumulative shows the cumulative fraction of values up to the upper edge of each bin.
+	HistogramCumulative
+)
+
+// Maximum number of automatic histogram bins.
+const maxBins = 1000
+
+// binEdges calculates equal-width bin edges for sorted values without NaN.
+// Uses the given number of bins if positive, or the binning rule otherwise.
+// The range is used instead of the data range if it is not zero.
+// Appends to dst[:0].
+func binEdges(sorted []float64, bins int, rule Binning, rng [2]float64, dst []float64) []float64 {
+	lo, hi := rng[0], rng[1]
+	if lo == 0 && hi == 0 {
+		lo, hi = 0, 1
+		if len(sorted) > 0 {
+			lo, hi = sorted[0], sorted[len(sorted)-1]
+		}
+	}
+	if !(hi > lo) {
+		lo, hi = lo-0.5, hi+0.5
+	}
+
+	if bins <= 0 {
+		bins = autoBins(sorted, rule, hi-lo)
+	}
+
+	dst = dst[:0]
+	width := (hi - lo) / float64(bins)
+	for i := range bins {
+		dst = append(dst, lo+float64(i)*width)
+	}
+	return append(dst, hi)
+}
+
+// autoBins calculates the number of bins for a range of sorted values, following the binning rule.
+// Falls back to Sturges' rule if the Freedman–Diaconis bin width is zero.
+func autoBins(sorted []float64, rule Binning, span float64) int {
+	n := len(sorted)
+	if n == 0 {
+		return 1
+	}
+	bins := int(math.Ceil(math.Log2(float64(n)))) + 1
+	if rule == FreedmanDiaconisBinning {
+		iqr := quantile(sorted, 0.75) - quantile(sorted, 0.25)
+		if width := 2 * iqr / math.Cbrt(float64(n)); width > 0 {
+			bins = int(math.Ceil(span / width))
+		}
+	}
+	return min(max(bins, 1), maxBins)
+}
+
+// binCounts counts values per bin, ignoring NaN and values outside the edges.
+// The last bin includes its upper edge.
+// Appends to dst[:0], and returns the counts and the number of values counted.
+func binCounts(values []float64, edges []float64, dst []float64) ([]float64, int) {
+	dst = dst[:0]
+	for range len(edges) - 1 {
+		dst = append(dst, 0)
+	}
+	last := len(edges) - 1
+	total := 0
+	for _, v := range values {
+		if !(v >= edges[0] && v <= edges[last]) {
+			continue
+		}
+		bin := sort.SearchFloat64s(edges, v)
+		if edges[bin] > v {
+			bin--
+		}
+		dst[min(bin, last-1)]++
+		total++
+	}
+	return dst, total
+}
+
+// normalize bin counts in place, given the total number of values.
+func (n HistogramNorm) normalize(counts []float64, edges []float64, total int) {
+	if total == 0 {
+		return
+	}
+	switch n {
+	case HistogramDensity:
+		for i := range counts {
+			counts[i] /= float64(total) * (edges[i+1] - edges[i])
+		}
+	case HistogramCumulative:
+		sum := 0.0
+		for i, c := range counts {
+			sum += c
+			counts[i] = sum / float64(total)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package plot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinEdges(t *testing.T) {
+	sorted := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8}
+
+	edges := binEdges(sorted, 4, SturgesBinning, [2]float64{}, nil)
+	assert.Equal(t, []float64{0, 2, 4, 6, 8}, edges)
+
+	edges = binEdges(sorted, 2, SturgesBinning, [2]float64{-10, 10}, edges)
+	assert.Equal(t, []float64{-10, 0, 10}, edges)
+
+	edges = binEdges(sorted, 0, SturgesBinning, [2]float64{}, edges)
+	assert.Equal(t, 5, len(edges)-1)
+
+	edges = binEdges(nil, 0, SturgesBinning, [2]float64{}, edges)
+	assert.Equal(t, []float64{0, 1}, edges)
+
+	edges = binEdges([]float64{3, 3}, 2, SturgesBinning, [2]float64{}, edges)
+	assert.Equal(t, []float64{2.5, 3, 3.5}, edges)
+}
+
+func TestAutoBins(t *testing.T) {
+	sorted := make([]float64, 1000)
+	for i := range sorted {
+		sorted[i] = float64(i)
+	}
+	assert.Equal(t, 11, autoBins(sorted, SturgesBinning, 999))
+	// IQR 499.5, width 99.9
+	assert.Equal(t, 10, autoBins(sorted, FreedmanDiaconisBinning, 999))
+	// Falls back to Sturges for zero IQR.
+	assert.Equal(t, 4, autoBins([]float64{1, 1, 1, 1, 5}, FreedmanDiaconisBinning, 4))
+	assert.Equal(t, 1, autoBins(nil, FreedmanDiaconisBinning, 1))
+	assert.Equal(t, maxBins, autoBins([]float64{0, 0, 0, 1, 1, 1}, FreedmanDiaconisBinning, 1e9))
+}
+
+func TestBinCounts(t *testing.T) {
+	edges := []float64{0, 1, 2, 4}
+	counts, total := binCounts([]float64{-1, 0, 0.5, 1, 3, 4, 5, math.NaN()}, edges, nil)
+	assert.Equal(t, []float64{2, 1, 2}, counts)
+	assert.Equal(t, 5, total)
+
+	density := append([]float64(nil), counts...)
+	HistogramDensity.normalize(density, edges, total)
+	assert.Equal(t, []float64{0.4, 0.2, 0.2}, density)
+
+	HistogramCumulative.normalize(counts, edges, total)
+	assert.Equal(t, []float64{0.4, 0.6, 1}, counts)
+
+	counts, total = binCounts(nil, edges, counts)
+	HistogramDensity.normalize(counts, edges, total)
+	assert.Equal(t, []float64{0, 0, 0}, counts)
+}
@@ -0,0 +1,170 @@
+package plot
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark/ecs"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// BoxPlot plot drawer.
+//
+// Shows the distribution of raw values, like a trait of all entities, per column of the observer.
+// Draws a box per column, with quartiles, whiskers and outliers,
+// or a violin of the kernel density estimate, with the interquartile range and the median.
+// Replaces the complete data by the table provided by the observer on every update.
+//
+// Whiskers extend to the most extreme values within 1.5 times the interquartile range from the box.
+// NaN values are ignored.
+type BoxPlot struct {
+	Observer   observer.Table // Observer providing raw values, with a row per entity and a column per box.
+	Columns    []string       // Columns to show, by name. Optional, default all.
+	Violin     bool           // Draws violins of the kernel density instead of boxes. Optional.
+	Bandwidth  float64        // Kernel bandwidth for violins. Optional, default by Silverman's rule of thumb.
+	YLim       [2]float64     // Y axis limits. Optional, default auto.
+	Labels     Labels         // Labels for plot and axes. Optional.
+	Style      *Style         // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	YAxis      Axis           // Y axis configuration (scale, tick format, grid, ...). Optional.
+	Async      bool           // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath   string         // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+	ExportPath string         // File for exporting the data with Ctrl+E, format by extension. Optional, default time-stamped CSV.
+
+	indices  []int
+	headers  []string
+	columns  []dataColumn
+	sorted   []float64
+	boxes    []boxStats
+	scale    float64
+	style    *Style
+	renderer plotRenderer
+}
+
+// Initialize the drawer.
+func (b *BoxPlot) Initialize(w *ecs.World, _ *opengl.Window) {
+	b.Observer.Initialize(w)
+
+	headers := b.Observer.Header()
+
+	if len(b.Columns) == 0 {
+		b.indices = make([]int, len(headers))
+		for i := range b.indices {
+			b.indices[i] = i
+		}
+	} else {
+		b.indices = make([]int, len(b.Columns))
+		var ok bool
+		for i, col := range b.Columns {
+			b.indices[i], ok = find(headers, col)
+			if !ok {
+				panic(fmt.Sprintf("column '%s' not found", col))
+			}
+		}
+	}
+
+	b.headers = make([]string, len(b.indices))
+	b.columns = make([]dataColumn, len(b.indices))
+	for i, idx := range b.indices {
+		b.headers[i] = headers[idx]
+		b.columns[i].Name = headers[idx]
+	}
+	b.boxes = make([]boxStats, len(b.indices))
+
+	b.scale = calcScaleCorrection()
+	b.style = b.Style.resolve()
+	b.renderer = plotRenderer{}
+}
+
+// Update the drawer.
+func (b *BoxPlot) Update(w *ecs.World) {
+	b.Observer.Update(w)
+	b.renderer.Invalidate()
+}
+
+// UpdateInputs handles input events of the previous frame update.
+func (b *BoxPlot) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	b.renderer.HandleInputs(win, b.scale)
+	if savePressed(win) {
+		saveWindow(win, b.SavePath, b.scale, b.SaveAs)
+	}
+	if exportPressed(win) {
+		exportWindow(b.ExportPath, b.ExportData)
+	}
+}
+
+// Draw the drawer.
+func (b *BoxPlot) Draw(w *ecs.World, win *opengl.Window) {
+	b.renderer.Draw(win, b.scale, b.Async, func() *figure {
+		b.updateData(w)
+		return b.buildFigure()
+	})
+}
+
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (b *BoxPlot) SaveAs(path string, width, height vg.Length) error {
+	return b.renderer.Save(path, width, height)
+}
+
+// ExportData writes the current raw values of the plot to a file, with column headers from the observer.
+// The file format is selected by the extension. Supported are csv and json.
+func (b *BoxPlot) ExportData(path string) error {
+	return exportData(path, b.columns)
+}
+
+func (b *BoxPlot) buildFigure() *figure {
+	p := plot.New()
+	setLabels(p, b.Labels, b.style)
+	setAxis(&p.Y, b.YAxis)
+	addGrid(p, false, b.YAxis.Grid, b.style)
+
+	if b.YLim[0] != 0 || b.YLim[1] != 0 {
+		p.Y.Min = b.YLim[0]
+		p.Y.Max = b.YLim[1]
+	}
+
+	chart := &boxChart{
+		Boxes:       append([]boxStats(nil), b.boxes...),
+		Violin:      b.Violin,
+		Colors:      b.style.Colors,
+		LineStyle:   draw.LineStyle{Color: b.style.Foreground, Width: b.style.LineWidth},
+		MedianColor: b.style.Background,
+		OutlierStyle: draw.GlyphStyle{
+			Color:  b.style.Foreground,
+			Radius: b.style.MarkerSize,
+			Shape:  draw.CircleGlyph{},
+		},
+	}
+	p.Add(chart)
+	if len(b.headers) > 0 {
+		p.NominalX(b.headers...)
+	}
+
+	return &figure{plot: p, series: chart.series(b.headers)}
+}
+
+func (b *BoxPlot) updateData(w *ecs.World) {
+	rows := b.Observer.Values(w)
+	for i, idx := range b.indices {
+		values := b.columns[i].Values[:0]
+		b.sorted = b.sorted[:0]
+		for _, row := range rows {
+			values = append(values, row[idx])
+			if !math.IsNaN(row[idx]) {
+				b.sorted = append(b.sorted, row[idx])
+			}
+		}
+		b.columns[i].Values = values
+
+		sort.Float64s(b.sorted)
+		b.boxes[i] = newBoxStats(b.sorted)
+		if b.Violin {
+			b.boxes[i].estimateDensity(b.sorted, b.Bandwidth)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package plot_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/ark-pixel/plot"
+	"github.com/mlange-42/ark-pixel/window"
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleBoxPlot() {
+
+	// Create a new model.
+	app := app.New()
+
+	// Limit the the simulation speed.
+	app.TPS = 30
+
+	// Create a box plot.
+	// See below for the implementation of the TableObserver.
+	app.AddUISystem((&window.Window{}).
+		With(&plot.BoxPlot{
+			Observer: &TableObserver{},
+			Columns:  []string{"A", "B", "C"}, // Optional, defaults to all columns
+		}))
+
+	// Add a termination system that ends the simulation.
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+
+	app.Run()
+
+	// Run the simulation.
+	// Due to the use of the OpenGL UI system, the model must be run via [window.Run].
+	// Comment out the code line above, and uncomment the next line to run this example stand-alone.
+
+	// window.Run(app)
+
+	// Output:
+}
+
+func TestBoxPlot_Violin(t *testing.T) {
+	box := plot.BoxPlot{
+		Observer: &TableObserverNaN{},
+		Violin:   true,
+		YAxis:    plot.Axis{Grid: true},
+	}
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&box))
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "box.csv")
+	assert.Nil(t, box.ExportData(path))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 26, len(lines))
+}
+
+func TestBoxPlot_PanicColumns(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.BoxPlot{
+			Observer: &TableObserver{},
+			Columns:  []string{"A", "F"},
+		}))
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	assert.Panics(t, app.Run)
+}
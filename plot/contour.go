@@ -3,8 +3,8 @@ package plot
 import (
 	"fmt"
 	"image/color"
+	"slices"
 
-	pixel "github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/mlange-42/ark-tools/observer"
 	"github.com/mlange-42/ark/ecs"
@@ -13,70 +13,99 @@ import (
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
 	"gonum.org/v1/plot/vg/draw"
-	"gonum.org/v1/plot/vg/vgimg"
 )
 
 // Contour plot drawer.
 //
 // Plots a grid as a contours.
+// Optionally draws filled contour bands and inline level labels.
+// If no levels are given, they are calculated from the data on every draw.
 // For large grids, this is relatively slow.
 // Consider using [Image] instead.
 type Contour struct {
-	Observer   observer.Grid   // Observers providing a Grid for contours.
-	Levels     []float64       // Levels for iso lines. Optional.
-	Palette    palette.Palette // Color palette. Optional.
-	Labels     Labels          // Labels for plot and axes. Optional.
-	HideLegend bool            // Hides the legend.
-
-	data  plotGrid
-	scale float64
+	Observer     observer.Grid   // Observers providing a Grid for contours.
+	Levels       []float64       // Levels for iso lines. Optional, default automatic.
+	NumLevels    int             // Number of automatic levels. Optional, default 8.
+	Quantiles    bool            // Places automatic levels at equally spaced quantiles instead of equal intervals of the data range.
+	Filled       bool            // Draws filled contour bands.
+	LevelLabels  bool            // Draws level labels on iso lines.
+	Palette      palette.Palette // Color palette. Optional, default "viridis" (see [NamedPalette]).
+	Labels       Labels          // Labels for plot and axes. Optional.
+	Style        *Style          // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	HideLegend   bool            // Hides the legend.
+	HideColorBar bool            // Hides the color bar.
+	Async        bool            // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath     string          // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+
+	data     plotGrid
+	levels   []float64
+	scale    float64
+	style    *Style
+	renderer plotRenderer
 }
 
 // Initialize the drawer.
 func (c *Contour) Initialize(w *ecs.World, _ *opengl.Window) {
 	c.Observer.Initialize(w)
-	c.data = plotGrid{
-		Grid: c.Observer,
-	}
+	c.data = plotGrid{}
 	c.scale = calcScaleCorrection()
+	c.style = c.Style.resolve()
+
+	if c.Palette == nil {
+		c.Palette = defaultPalette()
+	}
+
+	c.renderer = plotRenderer{}
 }
 
 // Update the drawer.
 func (c *Contour) Update(w *ecs.World) {
 	c.Observer.Update(w)
+	c.renderer.Invalidate()
 }
 
 // UpdateInputs handles input events of the previous frame update.
-func (c *Contour) UpdateInputs(_ *ecs.World, _ *opengl.Window) {}
+func (c *Contour) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	c.renderer.HandleInputs(win, c.scale)
+	if savePressed(win) {
+		saveWindow(win, c.SavePath, c.scale, c.SaveAs)
+	}
+}
 
 // Draw the drawer.
 func (c *Contour) Draw(w *ecs.World, win *opengl.Window) {
-	width := win.Canvas().Bounds().W()
-	height := win.Canvas().Bounds().H()
-
-	c.updateData(w)
+	c.renderer.Draw(win, c.scale, c.Async, func() *figure {
+		c.updateData(w)
+		return c.buildFigure()
+	})
+}
 
-	canvas := vgimg.New(vg.Points(width*c.scale)-10, vg.Points(height*c.scale)-10)
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (c *Contour) SaveAs(path string, width, height vg.Length) error {
+	return c.renderer.Save(path, width, height)
+}
 
+func (c *Contour) buildFigure() *figure {
 	p := plot.New()
-	setLabels(p, c.Labels)
+	setLabels(p, c.Labels, c.style)
 
 	p.X.Tick.Marker = removeLastTicks{}
 
 	cols := c.Palette.Colors()
-	min := 0.0
-	max := 1.0
 	if len(c.Levels) > 0 {
-		min = c.Levels[0]
-		max = c.Levels[len(c.Levels)-1]
+		// Sorted copy, as filled bands require sorted levels, and gonum sorts them in-place.
+		c.levels = slices.Sorted(slices.Values(c.Levels))
 	} else {
-		c.Levels = []float64{0.01, 0.05, 0.25, 0.5, 0.75, 0.95, 0.99}
+		c.levels = calcLevels(c.data.Values, c.NumLevels, c.Quantiles)
 	}
+	min := c.levels[0]
+	max := c.levels[len(c.levels)-1]
 
 	contours := plotter.Contour{
 		GridXYZ:    &c.data,
-		Levels:     c.Levels,
-		LineStyles: []draw.LineStyle{plotter.DefaultLineStyle},
+		Levels:     c.levels,
+		LineStyles: []draw.LineStyle{{Color: c.style.Foreground, Width: c.style.LineWidth}},
 		Palette:    c.Palette,
 		Underflow:  cols[0],
 		Overflow:   cols[len(cols)-1],
@@ -85,25 +114,41 @@ func (c *Contour) Draw(w *ecs.World, win *opengl.Window) {
 	}
 
 	if !c.HideLegend {
-		p.Legend = plot.NewLegend()
-		p.Legend.TextStyle.Font.Variant = "Mono"
+		p.Legend = newLegend(c.style)
 		c.populateLegend(&p.Legend, &contours)
 	}
 
+	if c.Filled {
+		p.Add(&contourBands{
+			GridXYZ: &c.data,
+			Levels:  c.levels,
+			Colors:  bandColors(cols, len(c.levels)+1),
+		})
+		// Draw iso lines in a neutral color on top of the bands.
+		contours.Palette = nil
+		contours.LineStyles[0].Color = color.RGBA{60, 60, 60, 255}
+		contours.LineStyles[0].Width = vg.Points(0.5)
+	}
 	p.Add(&contours)
 
-	win.Clear(color.White)
-	p.Draw(draw.New(canvas))
-
-	img := canvas.Image()
-	picture := pixel.PictureDataFromImage(img)
+	if c.LevelLabels {
+		p.Add(&contourLabels{
+			GridXYZ:    &c.data,
+			Levels:     c.levels,
+			TextStyle:  p.X.Tick.Label,
+			Background: p.BackgroundColor,
+		})
+	}
 
-	sprite := pixel.NewSprite(picture, picture.Bounds())
-	sprite.Draw(win, pixel.IM.Moved(pixel.V(picture.Rect.W()/2.0+5, picture.Rect.H()/2.0+5)))
+	fig := figure{plot: p}
+	if !c.HideColorBar {
+		fig.colorBar = &colorBar{Colors: cols, Min: min, Max: max}
+	}
+	return &fig
 }
 
 func (c *Contour) updateData(w *ecs.World) {
-	c.data.Values = c.Observer.Values(w)
+	c.data.update(c.Observer, w)
 }
 
 func (c *Contour) populateLegend(legend *plot.Legend, contours *plotter.Contour) {
@@ -111,12 +156,12 @@ func (c *Contour) populateLegend(legend *plot.Legend, contours *plotter.Contour)
 	if c.Palette != nil {
 		pal = c.Palette.Colors()
 	}
-	ps := float64(len(pal)-1) / (c.Levels[len(c.Levels)-1] - c.Levels[0])
-	if len(c.Levels) == 1 {
+	ps := float64(len(pal)-1) / (c.levels[len(c.levels)-1] - c.levels[0])
+	if len(c.levels) == 1 {
 		ps = 0
 	}
-	for i := len(c.Levels) - 1; i >= 0; i-- {
-		z := c.Levels[i]
+	for i := len(c.levels) - 1; i >= 0; i-- {
+		z := c.levels[i]
 		var col color.Color
 		switch {
 		case z < contours.Min:
@@ -126,12 +171,25 @@ func (c *Contour) populateLegend(legend *plot.Legend, contours *plotter.Contour)
 		case len(pal) == 0:
 			col = contours.Underflow
 		default:
-			col = pal[int((z-c.Levels[0])*ps+0.5)] // Apply palette scaling.
+			col = pal[int((z-c.levels[0])*ps+0.5)] // Apply palette scaling.
 		}
 		legend.Add(fmt.Sprintf("%f", z), colorThumbnailer{col})
 	}
 }
 
+// bandColors selects evenly spaced colors from a palette for the given number of contour bands.
+func bandColors(pal []color.Color, bands int) []color.Color {
+	cols := make([]color.Color, bands)
+	if bands == 1 {
+		cols[0] = pal[len(pal)/2]
+		return cols
+	}
+	for i := range cols {
+		cols[i] = pal[i*(len(pal)-1)/(bands-1)]
+	}
+	return cols
+}
+
 // colorThumbnailer implements the Thumbnailer interface.
 type colorThumbnailer struct {
 	color color.Color
@@ -0,0 +1,290 @@
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Default number of automatic contour levels.
+const defaultNumLevels = 8
+
+// Minimum distance between contour labels, in points.
+const contourLabelSpacing = 80
+
+// Maximum number of labels per contour level.
+const contourLabelsPerLevel = 3
+
+// calcLevels calculates contour levels from the data.
+// Levels are either equally spaced in the data range, excluding minimum and maximum,
+// or placed at equally spaced quantiles.
+// NaN values are ignored.
+func calcLevels(values []float64, n int, quantiles bool) []float64 {
+	if n <= 0 {
+		n = defaultNumLevels
+	}
+
+	data := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			data = append(data, v)
+		}
+	}
+	if len(data) == 0 {
+		return []float64{0}
+	}
+
+	levels := make([]float64, 0, n)
+	if quantiles {
+		sort.Float64s(data)
+		for i := range n {
+			levels = append(levels, quantile(data, float64(i+1)/float64(n+1)))
+		}
+	} else {
+		min, max := data[0], data[0]
+		for _, v := range data {
+			min = math.Min(min, v)
+			max = math.Max(max, v)
+		}
+		step := (max - min) / float64(n+1)
+		for i := range n {
+			levels = append(levels, min+float64(i+1)*step)
+		}
+	}
+
+	// Remove duplicates, e.g. from quantiles of discrete data.
+	unique := levels[:1]
+	for _, l := range levels[1:] {
+		if l > unique[len(unique)-1] {
+			unique = append(unique, l)
+		}
+	}
+	return unique
+}
+
+// quantile of sorted data, with linear interpolation.
+func quantile(sorted []float64, q float64) float64 {
+	h := float64(len(sorted)-1) * q
+	i := int(h)
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (h-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+// contourBands is a plotter for filled contour bands.
+//
+// Each grid cell is treated as a polygon between the cell centers of four neighboring grid cells.
+// Per band, the polygon is clipped to the band's value range, using linear interpolation along the edges.
+type contourBands struct {
+	GridXYZ plotter.GridXYZ
+	Levels  []float64     // Sorted levels. Bands are below the first, between consecutive and above the last level.
+	Colors  []color.Color // Band colors, one more than levels.
+}
+
+type bandVertex struct {
+	X, Y, Z float64
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (b *contourBands) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	cols, rows := b.GridXYZ.Dims()
+
+	poly := make([]bandVertex, 0, 8)
+	lower := make([]bandVertex, 0, 8)
+	upper := make([]bandVertex, 0, 8)
+	points := make([]vg.Point, 0, 8)
+
+	for col := 0; col < cols-1; col++ {
+		for row := 0; row < rows-1; row++ {
+			poly = append(poly[:0],
+				bandVertex{b.GridXYZ.X(col), b.GridXYZ.Y(row), b.GridXYZ.Z(col, row)},
+				bandVertex{b.GridXYZ.X(col + 1), b.GridXYZ.Y(row), b.GridXYZ.Z(col+1, row)},
+				bandVertex{b.GridXYZ.X(col + 1), b.GridXYZ.Y(row + 1), b.GridXYZ.Z(col+1, row+1)},
+				bandVertex{b.GridXYZ.X(col), b.GridXYZ.Y(row + 1), b.GridXYZ.Z(col, row+1)},
+			)
+
+			minBand, maxBand := len(b.Levels), 0
+			valid := true
+			for _, v := range poly {
+				if math.IsNaN(v.Z) {
+					valid = false
+					break
+				}
+				band := b.band(v.Z)
+				minBand = min(minBand, band)
+				maxBand = max(maxBand, band)
+			}
+			if !valid {
+				continue
+			}
+
+			for band := minBand; band <= maxBand; band++ {
+				clipped := poly
+				if minBand != maxBand {
+					if band > 0 {
+						lower = clipBand(lower, clipped, b.Levels[band-1], true)
+						clipped = lower
+					}
+					if band < len(b.Levels) {
+						upper = clipBand(upper, clipped, b.Levels[band], false)
+						clipped = upper
+					}
+				}
+				if len(clipped) < 3 {
+					continue
+				}
+				points = points[:0]
+				for _, v := range clipped {
+					points = append(points, vg.Point{X: trX(v.X), Y: trY(v.Y)})
+				}
+				points = c.ClipPolygonXY(points)
+				if len(points) < 3 {
+					continue
+				}
+				c.FillPolygon(b.Colors[band], points)
+				// Stroke outlines in the same color to hide anti-aliasing seams between cells.
+				points = append(points, points[0])
+				c.StrokeLines(draw.LineStyle{Color: b.Colors[band], Width: vg.Points(0.5)}, points)
+			}
+		}
+	}
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+func (b *contourBands) DataRange() (xmin, xmax, ymin, ymax float64) {
+	cols, rows := b.GridXYZ.Dims()
+	return b.GridXYZ.X(0), b.GridXYZ.X(cols - 1), b.GridXYZ.Y(0), b.GridXYZ.Y(rows - 1)
+}
+
+// band returns the index of the band for a value.
+func (b *contourBands) band(z float64) int {
+	return sort.Search(len(b.Levels), func(i int) bool { return b.Levels[i] > z })
+}
+
+// clipBand clips a polygon to the values above (or below) the given level.
+// Appends to dst[:0], which must not share memory with the polygon.
+func clipBand(dst, poly []bandVertex, level float64, above bool) []bandVertex {
+	inside := func(v bandVertex) bool {
+		if above {
+			return v.Z >= level
+		}
+		return v.Z < level
+	}
+
+	dst = dst[:0]
+	for i, curr := range poly {
+		prev := poly[(i+len(poly)-1)%len(poly)]
+		currIn, prevIn := inside(curr), inside(prev)
+		if currIn != prevIn {
+			t := (level - prev.Z) / (curr.Z - prev.Z)
+			dst = append(dst, bandVertex{
+				X: prev.X + t*(curr.X-prev.X),
+				Y: prev.Y + t*(curr.Y-prev.Y),
+				Z: level,
+			})
+		}
+		if currIn {
+			dst = append(dst, curr)
+		}
+	}
+	return dst
+}
+
+// contourLabels is a plotter for inline labels on contour lines.
+//
+// Labels are placed on crossings of the iso lines with grid cell edges,
+// with a minimum distance between labels.
+type contourLabels struct {
+	GridXYZ    plotter.GridXYZ
+	Levels     []float64
+	TextStyle  draw.TextStyle
+	Background color.Color
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (l *contourLabels) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	cols, rows := l.GridXYZ.Dims()
+
+	sty := l.TextStyle
+	sty.XAlign = draw.XCenter
+	sty.YAlign = draw.YCenter
+
+	placed := []vg.Point{}
+	candidates := []vg.Point{}
+	for _, z := range l.Levels {
+		candidates = candidates[:0]
+		for col := 0; col < cols; col++ {
+			for row := 0; row < rows; row++ {
+				x, y, v := l.GridXYZ.X(col), l.GridXYZ.Y(row), l.GridXYZ.Z(col, row)
+				if col < cols-1 {
+					x2, v2 := l.GridXYZ.X(col+1), l.GridXYZ.Z(col+1, row)
+					if t, ok := crossing(v, v2, z); ok {
+						candidates = append(candidates, vg.Point{X: trX(x + t*(x2-x)), Y: trY(y)})
+					}
+				}
+				if row < rows-1 {
+					y2, v2 := l.GridXYZ.Y(row+1), l.GridXYZ.Z(col, row+1)
+					if t, ok := crossing(v, v2, z); ok {
+						candidates = append(candidates, vg.Point{X: trX(x), Y: trY(y + t*(y2-y))})
+					}
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		label := fmt.Sprintf("%.4g", z)
+		rect := sty.Rectangle(label)
+		cnt := 0
+		// Try candidates spread evenly along the scan order.
+		for k := 0; k < 4*contourLabelsPerLevel && cnt < contourLabelsPerLevel; k++ {
+			pt := candidates[(2*k+1)*len(candidates)/(8*contourLabelsPerLevel)]
+			if !c.Contains(pt) || isNear(pt, placed, contourLabelSpacing) {
+				continue
+			}
+			if l.Background != nil {
+				c.FillPolygon(l.Background, []vg.Point{
+					{X: pt.X + rect.Min.X, Y: pt.Y + rect.Min.Y},
+					{X: pt.X + rect.Max.X, Y: pt.Y + rect.Min.Y},
+					{X: pt.X + rect.Max.X, Y: pt.Y + rect.Max.Y},
+					{X: pt.X + rect.Min.X, Y: pt.Y + rect.Max.Y},
+				})
+			}
+			c.FillText(sty, pt, label)
+			placed = append(placed, pt)
+			cnt++
+		}
+	}
+}
+
+// crossing returns the relative position of a level between two values, if the level is crossed.
+func crossing(v1, v2, level float64) (float64, bool) {
+	if math.IsNaN(v1) || math.IsNaN(v2) || v1 == v2 {
+		return 0, false
+	}
+	if (v1 < level) == (v2 < level) {
+		return 0, false
+	}
+	return (level - v1) / (v2 - v1), true
+}
+
+// isNear checks whether a point is closer than dist to any of the given points.
+func isNear(pt vg.Point, points []vg.Point, dist vg.Length) bool {
+	for _, p := range points {
+		dx, dy := pt.X-p.X, pt.Y-p.Y
+		if dx*dx+dy*dy < dist*dist {
+			return true
+		}
+	}
+	return false
+}
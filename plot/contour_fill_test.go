@@ -0,0 +1,83 @@
+package plot
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestCalcLevels(t *testing.T) {
+	values := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, math.NaN()}
+
+	levels := calcLevels(values, 4, false)
+	assert.Equal(t, []float64{2, 4, 6, 8}, levels)
+
+	levels = calcLevels(values, 0, false)
+	assert.Equal(t, defaultNumLevels, len(levels))
+
+	levels = calcLevels(values, 1, true)
+	assert.Equal(t, []float64{5}, levels)
+
+	levels = calcLevels([]float64{1, 1, 1, 2}, 3, true)
+	assert.Equal(t, []float64{1, 1.25}, levels)
+
+	levels = calcLevels([]float64{math.NaN()}, 3, false)
+	assert.Equal(t, []float64{0}, levels)
+}
+
+func TestClipBand(t *testing.T) {
+	square := []bandVertex{
+		{0, 0, 0},
+		{1, 0, 1},
+		{1, 1, 1},
+		{0, 1, 0},
+	}
+
+	above := clipBand(nil, square, 0.5, true)
+	assert.Equal(t, 4, len(above))
+	for _, v := range above {
+		assert.GreaterOrEqual(t, v.X, 0.5)
+	}
+
+	below := clipBand(above, square, 0.5, false)
+	assert.Equal(t, 4, len(below))
+	for _, v := range below {
+		assert.LessOrEqual(t, v.X, 0.5)
+	}
+
+	empty := clipBand(nil, square, 2, true)
+	assert.Equal(t, 0, len(empty))
+	assert.Equal(t, bandVertex{1, 0, 1}, square[1])
+}
+
+func TestCrossing(t *testing.T) {
+	pos, ok := crossing(0, 2, 0.5)
+	assert.True(t, ok)
+	assert.Equal(t, 0.25, pos)
+
+	_, ok = crossing(0, 2, 3)
+	assert.False(t, ok)
+
+	_, ok = crossing(math.NaN(), 2, 1)
+	assert.False(t, ok)
+}
+
+func TestContourUnsortedLevels(t *testing.T) {
+	c := Contour{
+		Levels:  []float64{0.5, 0.1, 0.9},
+		Filled:  true,
+		Palette: defaultPalette(),
+		style:   (*Style)(nil).resolve(),
+	}
+	c.data.update(&testGrid{values: []float64{0, 0.2, 0.4, 0.6, 0.8, 1}}, nil)
+
+	fig := c.buildFigure()
+	assert.Equal(t, []float64{0.1, 0.5, 0.9}, c.levels)
+	assert.Equal(t, []float64{0.5, 0.1, 0.9}, c.Levels)
+
+	path := filepath.Join(t.TempDir(), "contour.svg")
+	assert.Nil(t, saveFigure(fig, path, 4*vg.Inch, 3*vg.Inch))
+}
@@ -61,3 +61,24 @@ func TestContour_NoLevels(t *testing.T) {
 	})
 	app.Run()
 }
+
+func TestContour_Filled(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.FPS = 0
+
+	app.AddUISystem(
+		(&window.Window{}).
+			With(&plot.Contour{
+				Observer:    observer.MatrixToGrid(&MatrixObserver{}, nil, nil),
+				NumLevels:   5,
+				Quantiles:   true,
+				Filled:      true,
+				LevelLabels: true,
+			}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
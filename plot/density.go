@@ -0,0 +1,159 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Density is a mode for drawing scatter plots as 2D histograms of point counts.
+type Density uint8
+
+const (
+	// NoDensity draws individual points.
+	NoDensity Density = iota
+	// SquareDensity counts points in a grid of rectangular bins.
+	SquareDensity
+	// HexDensity counts points in a grid of hexagonal bins.
+	HexDensity
+)
+
+// Default number of density bins along the X axis.
+const defaultDensityBins = 50
+
+// densityGrid counts points in square or hexagonal bins over a rectangular range.
+//
+// Hexagonal bins are arranged in two interleaved lattices, like in matplotlib's hexbin.
+// The first lattice has its centers at the corners of a grid of Bins x Rows cells,
+// the second one at the centers of the cells. Rows is Bins / sqrt(3),
+// so that hexagons are regular for plots of equal width and height.
+type densityGrid struct {
+	Hex                    bool
+	Bins, Rows             int // Number of cells along X and Y.
+	XMin, XMax, YMin, YMax float64
+	Counts                 []float64 // Counts per bin. For hexagons, the first lattice is followed by the second one.
+	Total                  int       // Number of points counted.
+}
+
+// reset the grid to the given number of bins along X and the given range, and clears all counts.
+// Expands empty ranges.
+func (g *densityGrid) reset(hex bool, bins int, xmin, xmax, ymin, ymax float64) {
+	if !(xmax > xmin) {
+		xmin, xmax = xmin-0.5, xmax+0.5
+	}
+	if !(ymax > ymin) {
+		ymin, ymax = ymin-0.5, ymax+0.5
+	}
+	bins = max(bins, 1)
+	g.Hex, g.Bins, g.Rows = hex, bins, bins
+	g.XMin, g.XMax, g.YMin, g.YMax = xmin, xmax, ymin, ymax
+	size := bins * bins
+	if hex {
+		g.Rows = max(int(math.Round(float64(bins)/math.Sqrt(3))), 1)
+		size = (g.Bins+1)*(g.Rows+1) + g.Bins*g.Rows
+	}
+	g.Counts = g.Counts[:0]
+	for range size {
+		g.Counts = append(g.Counts, 0)
+	}
+	g.Total = 0
+}
+
+// add a point to the grid. Ignores NaN and points outside the range.
+func (g *densityGrid) add(x, y float64) {
+	if !(x >= g.XMin && x <= g.XMax && y >= g.YMin && y <= g.YMax) {
+		return
+	}
+	u := (x - g.XMin) / (g.XMax - g.XMin) * float64(g.Bins)
+	v := (y - g.YMin) / (g.YMax - g.YMin) * float64(g.Rows)
+	g.Total++
+
+	if !g.Hex {
+		col, row := min(int(u), g.Bins-1), min(int(v), g.Rows-1)
+		g.Counts[row*g.Bins+col]++
+		return
+	}
+
+	col1, row1 := math.Round(u), math.Round(v)
+	col2, row2 := math.Floor(u), math.Floor(v)
+	d1 := (u-col1)*(u-col1) + 3*(v-row1)*(v-row1)
+	d2 := (u-col2-0.5)*(u-col2-0.5) + 3*(v-row2-0.5)*(v-row2-0.5)
+	if d1 < d2 {
+		g.Counts[int(row1)*(g.Bins+1)+int(col1)]++
+		return
+	}
+	col, row := min(int(col2), g.Bins-1), min(int(row2), g.Rows-1)
+	g.Counts[(g.Bins+1)*(g.Rows+1)+row*g.Bins+col]++
+}
+
+// center returns the center of the bin with the given index, in data coordinates.
+func (g *densityGrid) center(i int) (x, y float64) {
+	var u, v float64
+	switch {
+	case !g.Hex:
+		u, v = float64(i%g.Bins)+0.5, float64(i/g.Bins)+0.5
+	case i < (g.Bins+1)*(g.Rows+1):
+		u, v = float64(i%(g.Bins+1)), float64(i/(g.Bins+1))
+	default:
+		i -= (g.Bins + 1) * (g.Rows + 1)
+		u, v = float64(i%g.Bins)+0.5, float64(i/g.Bins)+0.5
+	}
+	return g.XMin + u*(g.XMax-g.XMin)/float64(g.Bins), g.YMin + v*(g.YMax-g.YMin)/float64(g.Rows)
+}
+
+// shape returns the offsets of the corners of a bin from its center, in data coordinates.
+func (g *densityGrid) shape() [][2]float64 {
+	dx := (g.XMax - g.XMin) / float64(g.Bins)
+	dy := (g.YMax - g.YMin) / float64(g.Rows)
+	if !g.Hex {
+		return [][2]float64{{-dx / 2, -dy / 2}, {dx / 2, -dy / 2}, {dx / 2, dy / 2}, {-dx / 2, dy / 2}}
+	}
+	return [][2]float64{
+		{dx / 2, -dy / 6}, {dx / 2, dy / 6}, {0, dy / 3},
+		{-dx / 2, dy / 6}, {-dx / 2, -dy / 6}, {0, -dy / 3},
+	}
+}
+
+// maxCount returns the highest count of all bins.
+func (g *densityGrid) maxCount() float64 {
+	m := 0.0
+	for _, c := range g.Counts {
+		m = math.Max(m, c)
+	}
+	return m
+}
+
+// densityPlotter is a plotter for the bins of a density grid, colored by their count.
+// Empty bins are left out.
+type densityPlotter struct {
+	Grid   densityGrid
+	Colors []color.Color
+	Min    float64 // Count for the first color.
+	Max    float64 // Count for the last color.
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (d *densityPlotter) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	m := valueMapping{Min: d.Min, Max: d.Max}
+	shape := d.Grid.shape()
+	pts := make([]vg.Point, len(shape))
+	for i, count := range d.Grid.Counts {
+		if count == 0 {
+			continue
+		}
+		x, y := d.Grid.center(i)
+		for j, off := range shape {
+			pts[j] = vg.Point{X: trX(x + off[0]), Y: trY(y + off[1])}
+		}
+		c.FillPolygon(m.color(d.Colors, count), c.ClipPolygonXY(pts))
+	}
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+func (d *densityPlotter) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return d.Grid.XMin, d.Grid.XMax, d.Grid.YMin, d.Grid.YMax
+}
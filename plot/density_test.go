@@ -0,0 +1,74 @@
+package plot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDensityGridSquare(t *testing.T) {
+	g := densityGrid{}
+	g.reset(false, 2, 0, 2, 0, 2)
+	assert.Equal(t, 2, g.Rows)
+	assert.Len(t, g.Counts, 4)
+
+	g.add(0.5, 0.5)
+	g.add(1.5, 0.5)
+	g.add(2, 2)
+	g.add(2, 2)
+	g.add(3, 1)
+	g.add(math.NaN(), 1)
+
+	assert.Equal(t, []float64{1, 1, 0, 2}, g.Counts)
+	assert.Equal(t, 4, g.Total)
+	assert.Equal(t, 2.0, g.maxCount())
+
+	x, y := g.center(3)
+	assert.Equal(t, 1.5, x)
+	assert.Equal(t, 1.5, y)
+	assert.Len(t, g.shape(), 4)
+
+	g.reset(false, 2, 1, 1, 0, 0)
+	assert.Equal(t, []float64{0, 0, 0, 0}, g.Counts)
+	assert.Equal(t, 0, g.Total)
+	assert.Equal(t, 0.5, g.XMin)
+	assert.Equal(t, 1.5, g.XMax)
+}
+
+func TestDensityGridHex(t *testing.T) {
+	g := densityGrid{}
+	g.reset(true, 4, 0, 4, 0, 2)
+	assert.Equal(t, 2, g.Rows)
+	assert.Len(t, g.Counts, 5*3+4*2)
+
+	// Points at bin centers of both lattices.
+	g.add(0, 0)
+	g.add(2, 1)
+	g.add(0.5, 0.5)
+	g.add(3.5, 1.5)
+
+	for i, c := range g.Counts {
+		x, y := g.center(i)
+		expected := 0.0
+		if (x == 0 && y == 0) || (x == 2 && y == 1) || (x == 0.5 && y == 0.5) || (x == 3.5 && y == 1.5) {
+			expected = 1
+		}
+		assert.Equal(t, expected, c, "bin at %f, %f", x, y)
+	}
+	assert.Len(t, g.shape(), 6)
+
+	// Every point is counted in the bin with the nearest center.
+	g.reset(true, 4, 0, 4, 0, 2)
+	for i := range 41 {
+		for j := range 21 {
+			g.add(float64(i)/10, float64(j)/10)
+		}
+	}
+	assert.Equal(t, 41*21, g.Total)
+	sum := 0.0
+	for _, c := range g.Counts {
+		sum += c
+	}
+	assert.Equal(t, float64(g.Total), sum)
+}
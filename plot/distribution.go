@@ -0,0 +1,220 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Width of boxes and violins, in units of the distance between categories.
+const boxWidth = 0.6
+
+// Number of points for the kernel density estimate of violins.
+const violinPoints = 64
+
+// boxStats are the summary statistics of a distribution for a box plot.
+//
+// Whiskers extend to the most extreme values within 1.5 times the interquartile range from the box.
+// Values beyond are outliers.
+type boxStats struct {
+	N                    int
+	Low, High            float64 // Ends of the whiskers.
+	Q1, Median, Q3       float64
+	Outliers             []float64
+	Min, Max             float64   // Range of all values, including outliers.
+	Density, DensityGrid []float64 // Kernel density estimate for violins, at the values of DensityGrid. Optional.
+	maxDensity           float64
+}
+
+// newBoxStats calculates box plot statistics from sorted values without NaN.
+func newBoxStats(sorted []float64) boxStats {
+	if len(sorted) == 0 {
+		return boxStats{}
+	}
+	b := boxStats{
+		N:      len(sorted),
+		Q1:     quantile(sorted, 0.25),
+		Median: quantile(sorted, 0.5),
+		Q3:     quantile(sorted, 0.75),
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+	}
+	iqr := b.Q3 - b.Q1
+	lo, hi := b.Q1-1.5*iqr, b.Q3+1.5*iqr
+	b.Low, b.High = b.Q1, b.Q3
+	for _, v := range sorted {
+		if v < lo || v > hi {
+			b.Outliers = append(b.Outliers, v)
+			continue
+		}
+		b.Low, b.High = math.Min(b.Low, v), math.Max(b.High, v)
+	}
+	return b
+}
+
+// estimateDensity calculates a Gaussian kernel density estimate over the range of the sorted values.
+// Uses Silverman's rule of thumb for the bandwidth if it is not positive.
+func (b *boxStats) estimateDensity(sorted []float64, bandwidth float64) {
+	b.Density, b.DensityGrid, b.maxDensity = nil, nil, 0
+	if len(sorted) == 0 {
+		return
+	}
+	if bandwidth <= 0 {
+		bandwidth = silverman(sorted)
+	}
+	b.Density = make([]float64, violinPoints)
+	b.DensityGrid = make([]float64, violinPoints)
+	step := (b.Max - b.Min) / (violinPoints - 1)
+	norm := 1 / (float64(len(sorted)) * bandwidth * math.Sqrt(2*math.Pi))
+	for i := range violinPoints {
+		y := b.Min + float64(i)*step
+		sum := 0.0
+		for _, v := range sorted {
+			z := (y - v) / bandwidth
+			sum += math.Exp(-0.5 * z * z)
+		}
+		b.DensityGrid[i] = y
+		b.Density[i] = sum * norm
+		b.maxDensity = math.Max(b.maxDensity, b.Density[i])
+	}
+}
+
+// silverman calculates the kernel bandwidth by Silverman's rule of thumb, for sorted values.
+// Returns 1 for values without spread.
+func silverman(sorted []float64) float64 {
+	n := float64(len(sorted))
+	mean := 0.0
+	for _, v := range sorted {
+		mean += v
+	}
+	mean /= n
+	variance := 0.0
+	for _, v := range sorted {
+		variance += (v - mean) * (v - mean)
+	}
+	sd := math.Sqrt(variance / math.Max(n-1, 1))
+	spread := sd
+	if iqr := (quantile(sorted, 0.75) - quantile(sorted, 0.25)) / 1.34; iqr > 0 {
+		spread = math.Min(sd, iqr)
+	}
+	if !(spread > 0) {
+		return 1
+	}
+	return 0.9 * spread * math.Pow(n, -0.2)
+}
+
+// boxChart is a plotter for box plots or violin plots of multiple distributions.
+// Distributions are placed at integer X positions. Empty distributions are left out.
+type boxChart struct {
+	Boxes        []boxStats
+	Violin       bool           // Draws violins instead of boxes. Requires densities of the boxes.
+	Colors       []color.Color  // Fill colors, cycled.
+	LineStyle    draw.LineStyle // Style of outlines, whiskers and medians.
+	OutlierStyle draw.GlyphStyle
+	MedianColor  color.Color // Color of the median marker in violins.
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (b *boxChart) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	half := boxWidth / 2
+	for i, box := range b.Boxes {
+		if box.N == 0 {
+			continue
+		}
+		x := float64(i)
+		fill := b.Colors[i%len(b.Colors)]
+
+		if b.Violin {
+			b.plotViolin(c, trX, trY, x, &box, fill)
+			continue
+		}
+
+		rect := []vg.Point{
+			{X: trX(x - half), Y: trY(box.Q1)}, {X: trX(x + half), Y: trY(box.Q1)},
+			{X: trX(x + half), Y: trY(box.Q3)}, {X: trX(x - half), Y: trY(box.Q3)},
+		}
+		c.FillPolygon(fill, c.ClipPolygonXY(rect))
+		c.StrokeLines(b.LineStyle, c.ClipLinesXY(append(rect, rect[0]))...)
+
+		median := b.LineStyle
+		median.Width *= 2
+		c.StrokeLines(median, c.ClipLinesXY([]vg.Point{{X: trX(x - half), Y: trY(box.Median)}, {X: trX(x + half), Y: trY(box.Median)}})...)
+
+		whisker := half / 2
+		c.StrokeLines(b.LineStyle, c.ClipLinesXY(
+			[]vg.Point{{X: trX(x), Y: trY(box.Q3)}, {X: trX(x), Y: trY(box.High)}},
+			[]vg.Point{{X: trX(x), Y: trY(box.Q1)}, {X: trX(x), Y: trY(box.Low)}},
+			[]vg.Point{{X: trX(x - whisker), Y: trY(box.High)}, {X: trX(x + whisker), Y: trY(box.High)}},
+			[]vg.Point{{X: trX(x - whisker), Y: trY(box.Low)}, {X: trX(x + whisker), Y: trY(box.Low)}},
+		)...)
+
+		for _, v := range box.Outliers {
+			pt := vg.Point{X: trX(x), Y: trY(v)}
+			if c.Contains(pt) {
+				c.DrawGlyph(b.OutlierStyle, pt)
+			}
+		}
+	}
+}
+
+// plotViolin draws a violin, with a line for the interquartile range and a marker for the median.
+func (b *boxChart) plotViolin(c draw.Canvas, trX, trY func(float64) vg.Length, x float64, box *boxStats, fill color.Color) {
+	half := boxWidth / 2
+	if box.maxDensity > 0 && box.Max > box.Min {
+		pts := make([]vg.Point, 0, 2*len(box.Density))
+		for i, d := range box.Density {
+			w := d / box.maxDensity * half
+			pts = append(pts, vg.Point{X: trX(x + w), Y: trY(box.DensityGrid[i])})
+		}
+		for i := len(box.Density) - 1; i >= 0; i-- {
+			w := box.Density[i] / box.maxDensity * half
+			pts = append(pts, vg.Point{X: trX(x - w), Y: trY(box.DensityGrid[i])})
+		}
+		c.FillPolygon(fill, c.ClipPolygonXY(pts))
+		c.StrokeLines(b.LineStyle, c.ClipLinesXY(append(pts, pts[0]))...)
+	}
+
+	c.StrokeLines(b.LineStyle, c.ClipLinesXY([]vg.Point{{X: trX(x), Y: trY(box.Low)}, {X: trX(x), Y: trY(box.High)}})...)
+	iqr := b.LineStyle
+	iqr.Width *= 4
+	c.StrokeLines(iqr, c.ClipLinesXY([]vg.Point{{X: trX(x), Y: trY(box.Q1)}, {X: trX(x), Y: trY(box.Q3)}})...)
+
+	pt := vg.Point{X: trX(x), Y: trY(box.Median)}
+	if c.Contains(pt) {
+		c.DrawGlyph(draw.GlyphStyle{Color: b.MedianColor, Radius: iqr.Width / 2, Shape: draw.CircleGlyph{}}, pt)
+	}
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+func (b *boxChart) DataRange() (xmin, xmax, ymin, ymax float64) {
+	ymin, ymax = math.Inf(1), math.Inf(-1)
+	for _, box := range b.Boxes {
+		if box.N > 0 {
+			ymin, ymax = math.Min(ymin, box.Min), math.Max(ymax, box.Max)
+		}
+	}
+	return -0.5, float64(len(b.Boxes)) - 0.5, ymin, ymax
+}
+
+// series returns the statistics of each box for the crosshair, named after the box and the statistic.
+func (b *boxChart) series(names []string) []namedSeries {
+	series := []namedSeries{}
+	for i, box := range b.Boxes {
+		if box.N == 0 {
+			continue
+		}
+		x := float64(i)
+		for _, stat := range []struct {
+			Name  string
+			Value float64
+		}{{"low", box.Low}, {"Q1", box.Q1}, {"median", box.Median}, {"Q3", box.Q3}, {"high", box.High}} {
+			series = append(series, namedSeries{Name: names[i] + " " + stat.Name, XYs: plotter.XYs{{X: x, Y: stat.Value}}})
+		}
+	}
+	return series
+}
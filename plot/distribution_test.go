@@ -0,0 +1,82 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+func TestBoxStats(t *testing.T) {
+	box := newBoxStats([]float64{-20, 1, 2, 3, 4, 5, 6, 7, 8, 9, 30})
+	assert.Equal(t, 11, box.N)
+	assert.Equal(t, []float64{2.5, 5, 7.5}, []float64{box.Q1, box.Median, box.Q3})
+	assert.Equal(t, []float64{1, 9}, []float64{box.Low, box.High})
+	assert.Equal(t, []float64{-20, 30}, box.Outliers)
+	assert.Equal(t, []float64{-20, 30}, []float64{box.Min, box.Max})
+
+	box = newBoxStats(nil)
+	assert.Equal(t, 0, box.N)
+	box.estimateDensity(nil, 0)
+	assert.Nil(t, box.Density)
+}
+
+func TestEstimateDensity(t *testing.T) {
+	sorted := []float64{0, 1, 1, 2, 2, 2, 3, 3, 4}
+	box := newBoxStats(sorted)
+	box.estimateDensity(sorted, 0.5)
+	assert.Equal(t, violinPoints, len(box.Density))
+	assert.Equal(t, 0.0, box.DensityGrid[0])
+	assert.InDelta(t, 4.0, box.DensityGrid[violinPoints-1], 1e-12)
+
+	// Symmetric data has a symmetric density, with the maximum at the center.
+	assert.InDelta(t, box.Density[0], box.Density[violinPoints-1], 1e-12)
+	assert.Greater(t, box.Density[violinPoints/2], box.Density[0])
+
+	// Density integrates to less than 1 over the data range, as the tails are cut.
+	sum := 0.0
+	for i := 1; i < violinPoints; i++ {
+		sum += (box.Density[i] + box.Density[i-1]) / 2 * (box.DensityGrid[i] - box.DensityGrid[i-1])
+	}
+	assert.Less(t, sum, 1.0)
+	assert.Greater(t, sum, 0.8)
+
+	// Standard deviation sqrt(1.5) is smaller than IQR/1.34 = 2/1.34.
+	assert.InDelta(t, 0.9*math.Sqrt(1.5)*math.Pow(9, -0.2), silverman(sorted), 1e-12)
+	assert.Equal(t, 1.0, silverman([]float64{2, 2, 2}))
+}
+
+func TestBoxChart(t *testing.T) {
+	sorted := []float64{-20, 1, 2, 3, 4, 5, 6, 7, 8, 9, 30}
+	box := newBoxStats(sorted)
+	box.estimateDensity(sorted, 0)
+
+	chart := &boxChart{
+		Boxes:        []boxStats{box, {}, box},
+		Colors:       []color.Color{color.White},
+		LineStyle:    draw.LineStyle{Color: color.Black, Width: 1},
+		OutlierStyle: draw.GlyphStyle{Color: color.Black, Radius: 2, Shape: draw.CircleGlyph{}},
+		MedianColor:  color.White,
+	}
+	xmin, xmax, ymin, ymax := chart.DataRange()
+	assert.Equal(t, []float64{-0.5, 2.5, -20, 30}, []float64{xmin, xmax, ymin, ymax})
+
+	series := chart.series([]string{"A", "B", "C"})
+	assert.Equal(t, 10, len(series))
+	assert.Equal(t, "A low", series[0].Name)
+	assert.Equal(t, "C median", series[7].Name)
+	assert.Equal(t, 2.0, series[7].XYs[0].X)
+
+	p := plot.New()
+	p.Add(chart)
+	path := filepath.Join(t.TempDir(), "box.svg")
+	assert.Nil(t, saveFigure(&figure{plot: p}, path, 4*vg.Inch, 3*vg.Inch))
+
+	chart.Violin = true
+	assert.Nil(t, saveFigure(&figure{plot: p}, path, 4*vg.Inch, 3*vg.Inch))
+}
@@ -1,5 +1,17 @@
 // Package plot provides live plotting drawers for window.Window,
 // using the gonum/plot package or direct OpenGL drawing.
 //
+// Drawers based on gonum/plot are interactive:
+//   - Mouse wheel: zoom in and out around the cursor
+//   - Left mouse button drag: pan
+//   - Right mouse button drag: zoom to box
+//   - R: reset zoom and pan
+//   - Ctrl+S: save the plot (see e.g. [TimeSeries.SaveAs])
+//   - Ctrl+E: export the plot data, where supported (see e.g. [TimeSeries.ExportData])
+//
+// A crosshair shows the cursor position in data coordinates, or the nearest data point and its series.
+//
+// Plot appearance is configured with a [Style], e.g. [DarkStyle] to match the dark monitor drawers.
+//
 // For the window, see package [github.com/mlange-42/ark-pixel/window].
 package plot
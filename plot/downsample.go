@@ -0,0 +1,247 @@
+package plot
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"gonum.org/v1/plot/plotter"
+)
+
+// Downsampling is a strategy for reducing the number of points of a data series,
+// while keeping its full range.
+type Downsampling uint8
+
+const (
+	// NoDownsampling keeps all points.
+	NoDownsampling Downsampling = iota
+	// LTTBDownsampling uses the Largest-Triangle-Three-Buckets algorithm,
+	// which preserves the visual shape of the data well.
+	LTTBDownsampling
+	// MinMaxDownsampling keeps the minimum and maximum of each bucket of points,
+	// which preserves peaks and the envelope of the data.
+	MinMaxDownsampling
+	// ReservoirDownsampling keeps a uniform random sample of points.
+	ReservoirDownsampling
+)
+
+// Default number of points per series for downsampling.
+const defaultMaxPoints = 1000
+
+// downsampler reduces aligned data series (i.e. with shared X values) to a bounded number of rows.
+//
+// Rows are selected jointly for all series, so that they stay aligned.
+// The first and the last row are always kept.
+type downsampler struct {
+	Method    Downsampling
+	MaxPoints int
+	count     int // Number of rows offered to the reservoir so far.
+}
+
+// Apply downsampling after a row was appended to the series.
+// Selection criteria are calculated from the series given by cols.
+//
+// LTTB and min/max downsampling compact the data to MaxPoints rows when it reaches twice that size.
+// Reservoir downsampling keeps exactly MaxPoints rows after each appended row.
+func (d *downsampler) Apply(series []plotter.XYs, cols []int) {
+	if d.Method == NoDownsampling || len(series) == 0 {
+		return
+	}
+	maxPoints := d.maxPoints()
+	rows := len(series[0])
+
+	switch d.Method {
+	case LTTBDownsampling:
+		if rows >= 2*maxPoints {
+			selectRows(series, lttb(series, cols, maxPoints))
+		}
+	case MinMaxDownsampling:
+		if rows >= 2*maxPoints {
+			selectRows(series, minMaxBuckets(series, cols, maxPoints))
+		}
+	case ReservoirDownsampling:
+		d.count++
+		if rows > maxPoints {
+			d.reservoir(series, maxPoints)
+		}
+	}
+}
+
+// reservoir applies reservoir sampling (algorithm R) to the second-last row,
+// which was the newest row before the latest one was appended.
+// The first row is kept fixed, and the last row is only a candidate on the next update.
+func (d *downsampler) reservoir(series []plotter.XYs, maxPoints int) {
+	rows := len(series[0])
+	candidate := rows - 2
+	j := rand.IntN(d.count)
+	remove := candidate
+	if j > 0 && j < maxPoints-1 {
+		remove = j
+	}
+	for i, s := range series {
+		series[i] = slices.Delete(s, remove, remove+1)
+	}
+}
+
+func (d *downsampler) maxPoints() int {
+	if d.MaxPoints <= 0 {
+		return defaultMaxPoints
+	}
+	return max(d.MaxPoints, 3)
+}
+
+// selectRows keeps only the given rows in all series.
+// Rows must be in increasing order.
+func selectRows(series []plotter.XYs, rows []int) {
+	for i, s := range series {
+		for k, r := range rows {
+			s[k] = s[r]
+		}
+		series[i] = s[:len(rows)]
+	}
+}
+
+// criteriaColumns returns the series to calculate selection criteria from.
+// Defaults to all series if cols is empty.
+func criteriaColumns(series []plotter.XYs, cols []int) []int {
+	if len(cols) > 0 {
+		return cols
+	}
+	return allRows(len(series))
+}
+
+// lttb selects n rows using the Largest-Triangle-Three-Buckets algorithm.
+// For multiple series, triangle areas are summed over series, normalized by each series' value range.
+func lttb(series []plotter.XYs, cols []int, n int) []int {
+	rows := len(series[0])
+	if n >= rows || n < 3 {
+		return allRows(rows)
+	}
+	cols = criteriaColumns(series, cols)
+	scales := make([]float64, len(cols))
+	for i, c := range cols {
+		scales[i] = 1 / valueRange(series[c])
+	}
+	xs := series[0]
+
+	selected := make([]int, 0, n)
+	selected = append(selected, 0)
+	every := float64(rows-2) / float64(n-2)
+	a := 0
+	avgY := make([]float64, len(cols))
+	for b := 0; b < n-2; b++ {
+		// Average of the next bucket.
+		avgStart := int(float64(b+1)*every) + 1
+		avgEnd := min(int(float64(b+2)*every)+1, rows)
+		avgX := 0.0
+		for r := avgStart; r < avgEnd; r++ {
+			avgX += xs[r].X
+		}
+		avgX /= float64(avgEnd - avgStart)
+		for i, c := range cols {
+			avgY[i] = meanY(series[c][avgStart:avgEnd])
+		}
+
+		// Point of the current bucket with the largest triangle area.
+		start := int(float64(b)*every) + 1
+		end := int(float64(b+1)*every) + 1
+		best, bestArea := start, -1.0
+		for r := start; r < end; r++ {
+			area := 0.0
+			for i, c := range cols {
+				pa, pr := series[c][a], series[c][r]
+				ar := math.Abs((pa.X-avgX)*(pr.Y-pa.Y) - (pa.X-pr.X)*(avgY[i]-pa.Y))
+				if !math.IsNaN(ar) {
+					area += ar * scales[i]
+				}
+			}
+			if area > bestArea {
+				best, bestArea = r, area
+			}
+		}
+		selected = append(selected, best)
+		a = best
+	}
+
+	return append(selected, rows-1)
+}
+
+// minMaxBuckets selects at most n rows, by keeping the rows with the minimum and maximum value
+// of each series in each bucket.
+func minMaxBuckets(series []plotter.XYs, cols []int, n int) []int {
+	rows := len(series[0])
+	if n >= rows || n < 3 {
+		return allRows(rows)
+	}
+	cols = criteriaColumns(series, cols)
+	buckets := max((n-2)/(2*len(cols)), 1)
+	size := float64(rows-2) / float64(buckets)
+
+	selected := make([]int, 0, n)
+	selected = append(selected, 0)
+	bucket := make([]int, 0, 2*len(cols))
+	for b := 0; b < buckets; b++ {
+		start := int(float64(b)*size) + 1
+		end := int(float64(b+1)*size) + 1
+		bucket = bucket[:0]
+		for _, c := range cols {
+			lo, hi := -1, -1
+			for r := start; r < end; r++ {
+				y := series[c][r].Y
+				if math.IsNaN(y) {
+					continue
+				}
+				if lo < 0 || y < series[c][lo].Y {
+					lo = r
+				}
+				if hi < 0 || y > series[c][hi].Y {
+					hi = r
+				}
+			}
+			if lo >= 0 {
+				bucket = append(bucket, lo, hi)
+			}
+		}
+		slices.Sort(bucket)
+		selected = append(selected, slices.Compact(bucket)...)
+	}
+
+	return append(selected, rows-1)
+}
+
+func allRows(rows int) []int {
+	all := make([]int, rows)
+	for i := range all {
+		all[i] = i
+	}
+	return all
+}
+
+// valueRange returns the range of Y values, ignoring NaN. Returns 1 for empty or constant series.
+func valueRange(s plotter.XYs) float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, pt := range s {
+		if !math.IsNaN(pt.Y) {
+			lo, hi = math.Min(lo, pt.Y), math.Max(hi, pt.Y)
+		}
+	}
+	if !(hi > lo) {
+		return 1
+	}
+	return hi - lo
+}
+
+// meanY returns the mean of Y values, ignoring NaN.
+func meanY(s plotter.XYs) float64 {
+	sum, cnt := 0.0, 0
+	for _, pt := range s {
+		if !math.IsNaN(pt.Y) {
+			sum += pt.Y
+			cnt++
+		}
+	}
+	if cnt == 0 {
+		return math.NaN()
+	}
+	return sum / float64(cnt)
+}
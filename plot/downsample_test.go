@@ -0,0 +1,91 @@
+package plot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/plotter"
+)
+
+func testSeries(rows int) []plotter.XYs {
+	a := make(plotter.XYs, rows)
+	b := make(plotter.XYs, rows)
+	for i := range rows {
+		a[i] = plotter.XY{X: float64(i), Y: math.Sin(float64(i) / 10)}
+		b[i] = plotter.XY{X: float64(i), Y: float64(i % 7)}
+	}
+	a[500].Y = 100
+	b[700].Y = -100
+	return []plotter.XYs{a, b}
+}
+
+func TestLTTB(t *testing.T) {
+	series := testSeries(1000)
+	rows := lttb(series, nil, 100)
+
+	assert.Len(t, rows, 100)
+	assert.Equal(t, 0, rows[0])
+	assert.Equal(t, 999, rows[99])
+	assert.True(t, isSorted(rows))
+	assert.Contains(t, rows, 500)
+	assert.Contains(t, rows, 700)
+
+	rows = lttb(series, []int{0}, 100)
+	assert.Contains(t, rows, 500)
+
+	assert.Len(t, lttb(series, nil, 2000), 1000)
+}
+
+func TestMinMaxBuckets(t *testing.T) {
+	series := testSeries(1000)
+	series[0][200].Y = math.NaN()
+	rows := minMaxBuckets(series, nil, 100)
+
+	assert.LessOrEqual(t, len(rows), 100)
+	assert.Equal(t, 0, rows[0])
+	assert.Equal(t, 999, rows[len(rows)-1])
+	assert.True(t, isSorted(rows))
+	assert.Contains(t, rows, 500)
+	assert.Contains(t, rows, 700)
+	assert.NotContains(t, rows, 200)
+}
+
+func TestDownsampler(t *testing.T) {
+	for _, method := range []Downsampling{LTTBDownsampling, MinMaxDownsampling, ReservoirDownsampling} {
+		d := downsampler{Method: method, MaxPoints: 50}
+		series := []plotter.XYs{{}, {}}
+		for i := range 1000 {
+			for j := range series {
+				series[j] = append(series[j], plotter.XY{X: float64(i), Y: float64(i * j)})
+			}
+			d.Apply(series, nil)
+
+			assert.LessOrEqual(t, len(series[0]), 100)
+			assert.Equal(t, len(series[0]), len(series[1]))
+			assert.Equal(t, 0.0, series[0][0].X)
+			assert.Equal(t, float64(i), series[0][len(series[0])-1].X)
+		}
+		for i := 1; i < len(series[0]); i++ {
+			assert.Greater(t, series[0][i].X, series[0][i-1].X)
+			assert.Equal(t, series[0][i].X, series[1][i].X)
+		}
+		if method == ReservoirDownsampling {
+			assert.Len(t, series[0], 50)
+		}
+	}
+
+	d := downsampler{}
+	series := testSeries(1000)
+	d.Apply(series, nil)
+	assert.Len(t, series[0], 1000)
+}
+
+func isSorted(rows []int) bool {
+	for i := 1; i < len(rows); i++ {
+		if rows[i] <= rows[i-1] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package plot
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	px "github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+)
+
+// dataColumn is a named column of plot data, for export.
+type dataColumn struct {
+	Name   string
+	Values []float64
+}
+
+// exportData writes columns of data to a file.
+// The file format is selected by the extension. Supported are csv and json.
+// Columns of different length are padded with NaN, which is written as null to JSON.
+func exportData(path string, columns []dataColumn) error {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("unsupported format: %q", format)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if format == "csv" {
+		err = writeCSV(f, columns)
+	} else {
+		err = writeJSON(f, columns)
+	}
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeCSV writes columns of data as CSV, with a header line.
+func writeCSV(w io.Writer, columns []dataColumn) error {
+	header, rows := toRows(columns)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(header))
+	for _, row := range rows {
+		for i, v := range row {
+			record[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSON writes columns of data as JSON, with a header and an array of rows.
+func writeJSON(w io.Writer, columns []dataColumn) error {
+	header, rows := toRows(columns)
+	data := struct {
+		Header []string      `json:"header"`
+		Rows   [][]jsonFloat `json:"rows"`
+	}{Header: header, Rows: make([][]jsonFloat, len(rows))}
+	for i, row := range rows {
+		data.Rows[i] = make([]jsonFloat, len(row))
+		for j, v := range row {
+			data.Rows[i][j] = jsonFloat(v)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&data)
+}
+
+// jsonFloat encodes NaN and infinite values as null, as they are not valid JSON.
+type jsonFloat float64
+
+// MarshalJSON implements the json.Marshaler interface.
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return []byte("null"), nil
+	}
+	return strconv.AppendFloat(nil, v, 'g', -1, 64), nil
+}
+
+// toRows converts columns to a header and rows, padding short columns with NaN.
+func toRows(columns []dataColumn) ([]string, [][]float64) {
+	header := make([]string, len(columns))
+	numRows := 0
+	for i, col := range columns {
+		header[i] = col.Name
+		numRows = max(numRows, len(col.Values))
+	}
+	rows := make([][]float64, numRows)
+	for r := range rows {
+		rows[r] = make([]float64, len(columns))
+		for c, col := range columns {
+			if r < len(col.Values) {
+				rows[r][c] = col.Values[r]
+			} else {
+				rows[r][c] = math.NaN()
+			}
+		}
+	}
+	return header, rows
+}
+
+// exportPressed checks whether the hotkey for exporting plot data (Ctrl+E) was pressed.
+func exportPressed(win *opengl.Window) bool {
+	return win.JustPressed(px.KeyE) && (win.Pressed(px.KeyLeftControl) || win.Pressed(px.KeyRightControl))
+}
+
+// exportWindow exports plot data using the given export function.
+// Uses a time-stamped CSV file name if path is empty.
+// Errors are logged rather than returned, as this is called from UI input handling.
+func exportWindow(path string, export func(path string) error) {
+	if path == "" {
+		path = fmt.Sprintf("data-%s.csv", time.Now().Format("20060102-150405"))
+	}
+	if err := export(path); err != nil {
+		log.Printf("ERROR: failed to export plot data to %s: %s", path, err)
+		return
+	}
+	log.Printf("Plot data exported to %s", path)
+}
@@ -0,0 +1,41 @@
+package plot
+
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCSV(t *testing.T) {
+	columns := []dataColumn{
+		{Name: "X", Values: []float64{0, 1, 2}},
+		{Name: "A", Values: []float64{0.5, math.NaN()}},
+	}
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, writeCSV(&buf, columns))
+	assert.Equal(t, "X,A\n0,0.5\n1,NaN\n2,NaN\n", buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	columns := []dataColumn{
+		{Name: "X", Values: []float64{0, 1}},
+		{Name: "A", Values: []float64{0.5, math.NaN()}},
+	}
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, writeJSON(&buf, columns))
+	assert.JSONEq(t, `{"header": ["X", "A"], "rows": [[0, 0.5], [1, null]]}`, buf.String())
+}
+
+func TestExportData(t *testing.T) {
+	dir := t.TempDir()
+	columns := []dataColumn{{Name: "X", Values: []float64{0, 1}}}
+
+	assert.Nil(t, exportData(filepath.Join(dir, "data.csv"), columns))
+	assert.Nil(t, exportData(filepath.Join(dir, "data.JSON"), columns))
+	assert.NotNil(t, exportData(filepath.Join(dir, "data.txt"), columns))
+}
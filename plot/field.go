@@ -3,39 +3,50 @@ package plot
 import (
 	"fmt"
 	"image/color"
+	"math"
 
-	pixel "github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/mlange-42/ark-tools/observer"
 	"github.com/mlange-42/ark/ecs"
 	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
 	"gonum.org/v1/plot/vg/draw"
-	"gonum.org/v1/plot/vg/vgimg"
 )
 
 // Field plot drawer.
 //
 // Plots a vector field from a GridLayers observer.
+// Draws arrows per grid cell, or streamlines computed from the two layers.
+// Arrows and streamlines can be colored by vector magnitude.
 // For large grids, this is relatively slow.
-// Consider using [ImageRGB] instead.
+// Consider using [ImageRGB] instead, or sub-sampling via Step.
 type Field struct {
-	Observer observer.GridLayers // Observers providing field component grids.
-	Labels   Labels              // Labels for plot and axes. Optional.
-	Layers   []int               // Layer indices. Optional, defaults to (0, 1).
-
-	data  plotField
-	scale float64
+	Observer     observer.GridLayers // Observers providing field component grids.
+	Labels       Labels              // Labels for plot and axes. Optional.
+	Style        *Style              // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	Layers       []int               // Layer indices. Optional, defaults to (0, 1).
+	Step         int                 // Draws only every Nth cell in each direction. For streamlines, the approximate line spacing in cells. Optional, default 1.
+	Scale        float64             // Arrow length scaling. With 1, the longest arrow spans one (sub-sampled) cell. Optional, default 1.
+	Normalize    bool                // Draws all arrows with equal length. Use together with Palette to show magnitudes.
+	Palette      palette.Palette     // Color palette for coloring by magnitude. Optional, default single color.
+	HideColorBar bool                // Hides the color bar. Only relevant if Palette is set.
+	Streamlines  bool                // Draws streamlines instead of arrows.
+	Async        bool                // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath     string              // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+
+	data     plotField
+	scale    float64
+	style    *Style
+	renderer plotRenderer
 }
 
 // Initialize the drawer.
 func (f *Field) Initialize(w *ecs.World, _ *opengl.Window) {
 	f.Observer.Initialize(w)
 
-	f.data = plotField{
-		GridLayers: f.Observer,
-	}
+	f.data = plotField{}
 
 	if f.Layers == nil {
 		f.Layers = []int{0, 1}
@@ -49,61 +60,155 @@ func (f *Field) Initialize(w *ecs.World, _ *opengl.Window) {
 		}
 	}
 
+	if f.Step <= 0 {
+		f.Step = 1
+	}
+	if f.Scale <= 0 {
+		f.Scale = 1
+	}
+
 	f.scale = calcScaleCorrection()
+	f.style = f.Style.resolve()
+	f.renderer = plotRenderer{}
 }
 
 // Update the drawer.
 func (f *Field) Update(w *ecs.World) {
 	f.Observer.Update(w)
+	f.renderer.Invalidate()
 }
 
 // UpdateInputs handles input events of the previous frame update.
-func (f *Field) UpdateInputs(_ *ecs.World, _ *opengl.Window) {}
+func (f *Field) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	f.renderer.HandleInputs(win, f.scale)
+	if savePressed(win) {
+		saveWindow(win, f.SavePath, f.scale, f.SaveAs)
+	}
+}
 
 // Draw the drawer.
 func (f *Field) Draw(w *ecs.World, win *opengl.Window) {
-	width := win.Canvas().Bounds().W()
-	height := win.Canvas().Bounds().H()
-
-	f.updateData(w)
+	f.renderer.Draw(win, f.scale, f.Async, func() *figure {
+		f.updateData(w)
+		return f.buildFigure()
+	})
+}
 
-	canvas := vgimg.New(vg.Points(width*f.scale)-10, vg.Points(height*f.scale)-10)
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (f *Field) SaveAs(path string, width, height vg.Length) error {
+	return f.renderer.Save(path, width, height)
+}
 
+func (f *Field) buildFigure() *figure {
 	p := plot.New()
-	setLabels(p, f.Labels)
+	setLabels(p, f.Labels, f.style)
 
 	p.X.Tick.Marker = removeLastTicks{}
 
-	field := plotter.NewField(&f.data)
-
-	p.Add(field)
-
-	win.Clear(color.White)
-	p.Draw(draw.New(canvas))
+	maxMag := f.data.MaxMagnitude()
+	var cols []color.Color
+	if f.Palette != nil {
+		cols = f.Palette.Colors()
+	}
 
-	img := canvas.Image()
-	picture := pixel.PictureDataFromImage(img)
+	if f.Streamlines {
+		p.Add(&fieldStreamlines{
+			Field:     &f.data,
+			Spacing:   f.Step,
+			Colors:    cols,
+			Max:       maxMag,
+			LineStyle: draw.LineStyle{Color: f.style.Foreground, Width: f.style.LineWidth},
+		})
+	} else {
+		p.Add(&fieldArrows{
+			Field:     &f.data,
+			Step:      f.Step,
+			Scale:     f.Scale,
+			Normalize: f.Normalize,
+			Colors:    cols,
+			Max:       maxMag,
+			LineStyle: draw.LineStyle{Color: f.style.Foreground, Width: f.style.LineWidth},
+		})
+	}
 
-	sprite := pixel.NewSprite(picture, picture.Bounds())
-	sprite.Draw(win, pixel.IM.Moved(pixel.V(picture.Rect.W()/2.0+5, picture.Rect.H()/2.0+5)))
+	fig := figure{plot: p}
+	if cols != nil && !f.HideColorBar {
+		fig.colorBar = &colorBar{Colors: cols, Min: 0, Max: maxMag}
+	}
+	return &fig
 }
 
 func (f *Field) updateData(w *ecs.World) {
+	width, height := f.Observer.Dims()
+	f.data.update(width, height, f.Observer.X, f.Observer.Y)
 	values := f.Observer.Values(w)
-	f.data.XValues = values[f.Layers[0]]
-	f.data.YValues = values[f.Layers[1]]
+	f.data.XValues = append(f.data.XValues[:0], values[f.Layers[0]]...)
+	f.data.YValues = append(f.data.YValues[:0], values[f.Layers[1]]...)
 }
 
+// plotField is a snapshot of the two layers of a vector field.
 type plotField struct {
-	observer.GridLayers
+	gridCoords
 	XValues []float64
 	YValues []float64
 }
 
 func (f *plotField) Vector(c, r int) plotter.XY {
-	w, _ := f.Dims()
 	return plotter.XY{
-		X: f.XValues[r*w+c],
-		Y: f.YValues[r*w+c],
+		X: f.XValues[r*f.Width+c],
+		Y: f.YValues[r*f.Width+c],
 	}
 }
+
+// CellSize returns the cell size in data coordinates.
+// Assumes a regular grid.
+func (f *plotField) CellSize() (float64, float64) {
+	w, h := f.Dims()
+	cw, ch := 1.0, 1.0
+	if w > 1 {
+		cw = f.X(1) - f.X(0)
+	}
+	if h > 1 {
+		ch = f.Y(1) - f.Y(0)
+	}
+	return cw, ch
+}
+
+// Interpolate the vector at the given position in grid coordinates, using bilinear interpolation.
+// Returns false if the position is outside the grid.
+func (f *plotField) Interpolate(u, v float64) (plotter.XY, bool) {
+	w, h := f.Dims()
+	if u < 0 || v < 0 || u > float64(w-1) || v > float64(h-1) {
+		return plotter.XY{}, false
+	}
+	c0, r0 := min(int(u), max(w-2, 0)), min(int(v), max(h-2, 0))
+	c1, r1 := min(c0+1, w-1), min(r0+1, h-1)
+	tu, tv := u-float64(c0), v-float64(r0)
+
+	v00, v10 := f.Vector(c0, r0), f.Vector(c1, r0)
+	v01, v11 := f.Vector(c0, r1), f.Vector(c1, r1)
+	return plotter.XY{
+		X: (1-tv)*((1-tu)*v00.X+tu*v10.X) + tv*((1-tu)*v01.X+tu*v11.X),
+		Y: (1-tv)*((1-tu)*v00.Y+tu*v10.Y) + tv*((1-tu)*v01.Y+tu*v11.Y),
+	}, true
+}
+
+// MaxMagnitude returns the maximum vector length, ignoring NaN.
+func (f *plotField) MaxMagnitude() float64 {
+	maxMag := 0.0
+	for i := range f.XValues {
+		mag := math.Hypot(f.XValues[i], f.YValues[i])
+		if mag > maxMag {
+			maxMag = mag
+		}
+	}
+	return maxMag
+}
+
+// DataRange returns the data range of the grid, including half a cell around the cell centers.
+func (f *plotField) DataRange() (xmin, xmax, ymin, ymax float64) {
+	w, h := f.Dims()
+	cw, ch := f.CellSize()
+	return f.X(0) - cw/2, f.X(w-1) + cw/2, f.Y(0) - ch/2, f.Y(h-1) + ch/2
+}
@@ -0,0 +1,251 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Angle of arrow heads to the arrow shaft, in radians.
+const arrowHeadAngle = 25 * math.Pi / 180
+
+// Length of arrow heads relative to the arrow length.
+const arrowHeadLength = 0.3
+
+// Integration step length for streamlines, in grid cells.
+const streamlineStep = 0.25
+
+// fieldArrows is a plotter for vector fields, drawing an arrow per (sub-sampled) grid cell.
+type fieldArrows struct {
+	Field     *plotField
+	Step      int           // Draw only every Nth cell.
+	Scale     float64       // Arrow length scale. With 1, the longest arrow spans one sub-sampled cell.
+	Normalize bool          // Draw all arrows with the same length.
+	Colors    []color.Color // Colors for mapping magnitudes. Optional.
+	Max       float64       // Maximum magnitude, for length and color scaling.
+	LineStyle draw.LineStyle
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (f *fieldArrows) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	cols, rows := f.Field.Dims()
+	cellW, cellH := f.Field.CellSize()
+
+	// Cell size in canvas units.
+	w := float64(trX(f.Field.X(0)+cellW)-trX(f.Field.X(0))) * float64(f.Step)
+	h := float64(trY(f.Field.Y(0)+cellH)-trY(f.Field.Y(0))) * float64(f.Step)
+	maxLen := math.Min(math.Abs(w), math.Abs(h)) * f.Scale
+
+	sty := f.LineStyle
+	for col := f.Step / 2; col < cols; col += f.Step {
+		for row := f.Step / 2; row < rows; row += f.Step {
+			v := f.Field.Vector(col, row)
+			mag := math.Hypot(v.X, v.Y)
+			if mag == 0 || math.IsNaN(mag) {
+				continue
+			}
+			// Direction in canvas space, considering different axis scales.
+			dx, dy := v.X*w/math.Abs(w), v.Y*h/math.Abs(h)
+			length := maxLen
+			if !f.Normalize && f.Max > 0 {
+				length *= mag / f.Max
+			}
+			norm := math.Hypot(dx, dy)
+			dx, dy = dx/norm*length, dy/norm*length
+
+			center := vg.Point{X: trX(f.Field.X(col)), Y: trY(f.Field.Y(row))}
+			if !c.Contains(center) {
+				continue
+			}
+			if len(f.Colors) > 0 {
+				sty.Color = colorAt(f.Colors, mag, 0, f.Max)
+			}
+			tail := vg.Point{X: center.X - vg.Length(dx/2), Y: center.Y - vg.Length(dy/2)}
+			tip := vg.Point{X: center.X + vg.Length(dx/2), Y: center.Y + vg.Length(dy/2)}
+			c.StrokeLine2(sty, tail.X, tail.Y, tip.X, tip.Y)
+			drawArrowHead(&c, sty, tip, math.Atan2(dy, dx), length*arrowHeadLength)
+		}
+	}
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+func (f *fieldArrows) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return f.Field.DataRange()
+}
+
+// fieldStreamlines is a plotter for vector fields, drawing streamlines.
+//
+// Streamlines are seeded on a regular grid and integrated in both directions,
+// using a second order Runge-Kutta scheme on the bilinearly interpolated field.
+// Integration stops when a line enters a seed cell that is already occupied by another line.
+type fieldStreamlines struct {
+	Field     *plotField
+	Spacing   int           // Approximate spacing between streamlines, in grid cells.
+	Colors    []color.Color // Colors for mapping magnitudes. Optional.
+	Max       float64       // Maximum magnitude, for color scaling.
+	LineStyle draw.LineStyle
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (f *fieldStreamlines) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	cols, rows := f.Field.Dims()
+	cellW, cellH := f.Field.CellSize()
+	x0, y0 := f.Field.X(0), f.Field.Y(0)
+
+	spacing := max(f.Spacing, 1)
+	occCols, occRows := (cols+spacing-1)/spacing, (rows+spacing-1)/spacing
+	occupied := make([]int, occCols*occRows)
+	for i := range occupied {
+		occupied[i] = -1
+	}
+
+	toCanvas := func(u, v float64) vg.Point {
+		return vg.Point{X: trX(x0 + u*cellW), Y: trY(y0 + v*cellH)}
+	}
+
+	forward := []streamPoint{}
+	backward := []streamPoint{}
+	line := []streamPoint{}
+	points := []vg.Point{}
+	id := 0
+	for oc := range occCols {
+		for orow := range occRows {
+			if occupied[orow*occCols+oc] >= 0 {
+				continue
+			}
+			u := math.Min(float64(oc*spacing)+0.5*float64(spacing), float64(cols-1))
+			v := math.Min(float64(orow*spacing)+0.5*float64(spacing), float64(rows-1))
+
+			forward = f.trace(forward[:0], u, v, 1, id, occupied, occCols, spacing)
+			backward = f.trace(backward[:0], u, v, -1, id, occupied, occCols, spacing)
+			if len(forward)+len(backward) < 3 {
+				// Release the cells of the skipped line, so that it does not block later lines.
+				releaseCells(occupied, forward, occCols, spacing)
+				releaseCells(occupied, backward, occCols, spacing)
+				continue
+			}
+			// Join backward (reversed, without duplicate seed) and forward parts.
+			line = line[:0]
+			for i := len(backward) - 1; i > 0; i-- {
+				line = append(line, backward[i])
+			}
+			line = append(line, forward...)
+
+			sty := f.LineStyle
+			if len(f.Colors) == 0 {
+				points = points[:0]
+				for _, pt := range line {
+					points = append(points, toCanvas(pt.U, pt.V))
+				}
+				c.StrokeLines(sty, c.ClipLinesXY(points)...)
+			} else {
+				for i := 1; i < len(line); i++ {
+					sty.Color = colorAt(f.Colors, line[i].Mag, 0, f.Max)
+					c.StrokeLines(sty, c.ClipLinesXY([]vg.Point{
+						toCanvas(line[i-1].U, line[i-1].V),
+						toCanvas(line[i].U, line[i].V),
+					})...)
+				}
+			}
+
+			// Arrow head in the middle of the line, pointing downstream.
+			mid := len(line) / 2
+			a, b := toCanvas(line[mid-1].U, line[mid-1].V), toCanvas(line[mid].U, line[mid].V)
+			if c.Contains(b) {
+				if len(f.Colors) > 0 {
+					sty.Color = colorAt(f.Colors, line[mid].Mag, 0, f.Max)
+				}
+				headLength := math.Min(math.Abs(float64(toCanvas(1, 0).X-toCanvas(0, 0).X)), math.Abs(float64(toCanvas(0, 1).Y-toCanvas(0, 0).Y)))
+				drawArrowHead(&c, sty, b, math.Atan2(float64(b.Y-a.Y), float64(b.X-a.X)), headLength*float64(spacing)*arrowHeadLength)
+			}
+			id++
+		}
+	}
+}
+
+// releaseCells marks the occupancy cells of the points of a line as free.
+func releaseCells(occupied []int, line []streamPoint, occCols int, spacing int) {
+	for _, pt := range line {
+		occupied[int(pt.V)/spacing*occCols+int(pt.U)/spacing] = -1
+	}
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+func (f *fieldStreamlines) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return f.Field.DataRange()
+}
+
+type streamPoint struct {
+	U, V float64 // Position in grid coordinates.
+	Mag  float64 // Vector magnitude.
+}
+
+// trace integrates a streamline from a seed position, in grid coordinates.
+// Direction is 1 for downstream and -1 for upstream.
+func (f *fieldStreamlines) trace(line []streamPoint, u, v float64, direction float64, id int, occupied []int, occCols int, spacing int) []streamPoint {
+	cols, rows := f.Field.Dims()
+	cellW, cellH := f.Field.CellSize()
+	maxSteps := int(4 * float64(cols+rows) / streamlineStep)
+
+	velocity := func(u, v float64) (float64, float64, float64, bool) {
+		vec, ok := f.Field.Interpolate(u, v)
+		if !ok {
+			return 0, 0, 0, false
+		}
+		mag := math.Hypot(vec.X, vec.Y)
+		if mag == 0 || math.IsNaN(mag) {
+			return 0, 0, 0, false
+		}
+		// Direction in grid coordinates, normalized to unit length.
+		du, dv := vec.X/cellW, vec.Y/cellH
+		norm := math.Hypot(du, dv)
+		return direction * du / norm, direction * dv / norm, mag, true
+	}
+
+	for range maxSteps {
+		du, dv, mag, ok := velocity(u, v)
+		if !ok {
+			break
+		}
+		occIdx := int(v)/spacing*occCols + int(u)/spacing
+		if occ := occupied[occIdx]; occ >= 0 && occ != id {
+			break
+		}
+		occupied[occIdx] = id
+		line = append(line, streamPoint{U: u, V: v, Mag: mag})
+
+		// Midpoint method.
+		du2, dv2, _, ok := velocity(u+0.5*streamlineStep*du, v+0.5*streamlineStep*dv)
+		if !ok {
+			break
+		}
+		u, v = u+streamlineStep*du2, v+streamlineStep*dv2
+	}
+	return line
+}
+
+// drawArrowHead draws an arrow head at the tip, for an arrow in the given direction.
+func drawArrowHead(c *draw.Canvas, sty draw.LineStyle, tip vg.Point, angle float64, length float64) {
+	for _, a := range []float64{angle + math.Pi - arrowHeadAngle, angle + math.Pi + arrowHeadAngle} {
+		c.StrokeLine2(sty, tip.X, tip.Y,
+			tip.X+vg.Length(length*math.Cos(a)), tip.Y+vg.Length(length*math.Sin(a)))
+	}
+}
+
+// colorAt maps a value to a color of the given colors, clamping to the range.
+func colorAt(cols []color.Color, v, min, max float64) color.Color {
+	if max <= min {
+		return cols[0]
+	}
+	idx := int((v-min)/(max-min)*float64(len(cols)-1) + 0.5)
+	return cols[clamp(idx, 0, len(cols)-1)]
+}
+
+func clamp[T int | float64](v, low, high T) T {
+	return max(low, min(v, high))
+}
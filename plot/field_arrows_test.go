@@ -0,0 +1,44 @@
+package plot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlotFieldInterpolate(t *testing.T) {
+	f := plotField{
+		gridCoords: gridCoords{Width: 2, Height: 2, XCoords: []float64{0, 1}, YCoords: []float64{0, 1}},
+		XValues:    []float64{0, 1, 0, 1},
+		YValues:    []float64{0, 0, 2, 2},
+	}
+
+	v, ok := f.Interpolate(0.5, 0.5)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, v.X, 1e-9)
+	assert.InDelta(t, 1.0, v.Y, 1e-9)
+
+	v, ok = f.Interpolate(1, 1)
+	assert.True(t, ok)
+	assert.InDelta(t, 1.0, v.X, 1e-9)
+	assert.InDelta(t, 2.0, v.Y, 1e-9)
+
+	_, ok = f.Interpolate(1.5, 0)
+	assert.False(t, ok)
+
+	assert.InDelta(t, 2.236, f.MaxMagnitude(), 0.001)
+}
+
+func TestReleaseCells(t *testing.T) {
+	occupied := []int{0, 2, 1, 2}
+	releaseCells(occupied, []streamPoint{{U: 2.5, V: 0.5}, {U: 3, V: 2.5}}, 2, 2)
+	assert.Equal(t, []int{0, -1, 1, -1}, occupied)
+}
+
+func TestColorAt(t *testing.T) {
+	cols := NamedPalette("greys", 3).Colors()
+	assert.Equal(t, cols[0], colorAt(cols, -1, 0, 1))
+	assert.Equal(t, cols[1], colorAt(cols, 0.5, 0, 1))
+	assert.Equal(t, cols[2], colorAt(cols, 2, 0, 1))
+	assert.Equal(t, cols[0], colorAt(cols, 2, 1, 1))
+}
@@ -2,6 +2,7 @@ package plot_test
 
 import (
 	"math"
+	"testing"
 
 	"github.com/mlange-42/ark-pixel/plot"
 	"github.com/mlange-42/ark-pixel/window"
@@ -42,6 +43,47 @@ func ExampleField() {
 	// Output:
 }
 
+func TestField_Arrows(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.FPS = 0
+
+	app.AddUISystem(
+		(&window.Window{}).
+			With(&plot.Field{
+				Observer:  observer.LayersToLayers(&FieldObserver{}, nil, nil),
+				Step:      3,
+				Scale:     0.8,
+				Normalize: true,
+				Palette:   plot.NamedPalette("magma", 0),
+			}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestField_Streamlines(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.FPS = 0
+
+	app.AddUISystem(
+		(&window.Window{}).
+			With(&plot.Field{
+				Observer:    observer.LayersToLayers(&FieldObserver{}, nil, nil),
+				Step:        2,
+				Streamlines: true,
+				Palette:     plot.NamedPalette("viridis", 0),
+			}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
 type FieldObserver struct {
 	cols   int
 	rows   int
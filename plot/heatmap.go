@@ -1,9 +1,6 @@
 package plot
 
 import (
-	"image/color"
-
-	pixel "github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/mlange-42/ark-tools/observer"
 	"github.com/mlange-42/ark/ecs"
@@ -11,8 +8,6 @@ import (
 	"gonum.org/v1/plot/palette"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
-	"gonum.org/v1/plot/vg/draw"
-	"gonum.org/v1/plot/vg/vgimg"
 )
 
 // HeatMap plot drawer.
@@ -21,49 +16,72 @@ import (
 // For large grids, this is relatively slow.
 // Consider using [Image] instead.
 type HeatMap struct {
-	Observer observer.Grid   // Observers providing a Grid for contours.
-	Palette  palette.Palette // Color palette. Optional.
-	Min      float64         // Minimum value for color mapping. Optional.
-	Max      float64         // Maximum value for color mapping. Optional. Is set to 1.0 if both Min and Max are zero.
-	Labels   Labels          // Labels for plot and axes. Optional.
-
-	data  plotGrid
-	scale float64
+	Observer     observer.Grid   // Observers providing a Grid for contours.
+	Palette      palette.Palette // Color palette. Optional, default "viridis" (see [NamedPalette]).
+	Min          float64         // Minimum value for color mapping. Optional.
+	Max          float64         // Maximum value for color mapping. Optional. Is set to 1.0 if both Min and Max are zero.
+	Labels       Labels          // Labels for plot and axes. Optional.
+	Style        *Style          // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	HideColorBar bool            // Hides the color bar.
+	Async        bool            // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath     string          // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+
+	data     plotGrid
+	scale    float64
+	style    *Style
+	renderer plotRenderer
 }
 
 // Initialize the drawer.
 func (h *HeatMap) Initialize(w *ecs.World, _ *opengl.Window) {
 	h.Observer.Initialize(w)
-	h.data = plotGrid{
-		Grid: h.Observer,
-	}
+	h.data = plotGrid{}
 
 	h.scale = calcScaleCorrection()
+	h.style = h.Style.resolve()
+
+	if h.Palette == nil {
+		h.Palette = defaultPalette()
+	}
 
 	if h.Min == 0 && h.Max == 0 {
 		h.Max = 1
 	}
+
+	h.renderer = plotRenderer{}
 }
 
 // Update the drawer.
 func (h *HeatMap) Update(w *ecs.World) {
 	h.Observer.Update(w)
+	h.renderer.Invalidate()
 }
 
 // UpdateInputs handles input events of the previous frame update.
-func (h *HeatMap) UpdateInputs(_ *ecs.World, _ *opengl.Window) {}
+func (h *HeatMap) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	h.renderer.HandleInputs(win, h.scale)
+	if savePressed(win) {
+		saveWindow(win, h.SavePath, h.scale, h.SaveAs)
+	}
+}
 
 // Draw the drawer.
 func (h *HeatMap) Draw(w *ecs.World, win *opengl.Window) {
-	width := win.Canvas().Bounds().W()
-	height := win.Canvas().Bounds().H()
-
-	h.updateData(w)
+	h.renderer.Draw(win, h.scale, h.Async, func() *figure {
+		h.updateData(w)
+		return h.buildFigure()
+	})
+}
 
-	c := vgimg.New(vg.Points(width*h.scale)-10, vg.Points(height*h.scale)-10)
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (h *HeatMap) SaveAs(path string, width, height vg.Length) error {
+	return h.renderer.Save(path, width, height)
+}
 
+func (h *HeatMap) buildFigure() *figure {
 	p := plot.New()
-	setLabels(p, h.Labels)
+	setLabels(p, h.Labels, h.style)
 
 	p.X.Tick.Marker = removeLastTicks{}
 
@@ -80,16 +98,13 @@ func (h *HeatMap) Draw(w *ecs.World, win *opengl.Window) {
 
 	p.Add(&heat)
 
-	win.Clear(color.White)
-	p.Draw(draw.New(c))
-
-	img := c.Image()
-	picture := pixel.PictureDataFromImage(img)
-
-	sprite := pixel.NewSprite(picture, picture.Bounds())
-	sprite.Draw(win, pixel.IM.Moved(pixel.V(picture.Rect.W()/2.0+5, picture.Rect.H()/2.0+5)))
+	fig := figure{plot: p}
+	if !h.HideColorBar {
+		fig.colorBar = &colorBar{Colors: cols, Min: h.Min, Max: h.Max}
+	}
+	return &fig
 }
 
 func (h *HeatMap) updateData(w *ecs.World) {
-	h.data.Values = h.Observer.Values(w)
+	h.data.update(h.Observer, w)
 }
@@ -1,6 +1,8 @@
 package plot_test
 
 import (
+	"testing"
+
 	"github.com/mlange-42/ark-pixel/plot"
 	"github.com/mlange-42/ark-pixel/window"
 	"github.com/mlange-42/ark-tools/app"
@@ -42,3 +44,42 @@ func ExampleHeatMap() {
 
 	// Output:
 }
+
+func TestHeatMap_NoPalette(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.FPS = 0
+
+	app.AddUISystem(
+		(&window.Window{}).
+			With(&plot.HeatMap{
+				Observer: observer.MatrixToGrid(&MatrixObserver{}, nil, nil),
+				Min:      -2,
+				Max:      2,
+			}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestHeatMap_Async(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.FPS = 0
+
+	app.AddUISystem(
+		(&window.Window{}).
+			With(&plot.HeatMap{
+				Observer: observer.MatrixToGrid(&MatrixObserver{}, nil, nil),
+				Min:      -2,
+				Max:      2,
+				Async:    true,
+			}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
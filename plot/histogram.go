@@ -0,0 +1,234 @@
+package plot
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark/ecs"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Histogram plot drawer.
+//
+// Bins raw values, like a property of all entities, and shows their distribution.
+// Values are taken from a table observer, with a row per entity and a histogram per column,
+// or from a row observer, with all values of the row in a single histogram.
+// Replaces the complete data by the values provided by the observer on every update.
+//
+// Multiple histograms are overlaid, with shared bin edges calculated from all shown values.
+// Bins have equal width, with their number given by Bins or by a Binning rule,
+// or are given explicitly by Edges.
+type Histogram struct {
+	Observer   observer.Table // Observer providing raw values, with a row per entity and a column per histogram. Alternative to Row.
+	Row        observer.Row   // Observer providing the raw values of a single histogram as one row. Alternative to Observer.
+	Columns    []string       // Columns to show, by name. Only used with Observer. Optional, default all.
+	Bins       int            // Number of equal-width bins. Optional, default determined by Binning.
+	Binning    Binning        // Rule for the number of bins, if Bins is not given. Optional, default Sturges' rule.
+	Range      [2]float64     // Range of equal-width bins. Values outside are ignored. Optional, default data range.
+	Edges      []float64      // Bin edges, in increasing order. Values outside are ignored. Overrides Bins, Binning and Range. Optional.
+	Normalize  HistogramNorm  // Normalization of bin heights: counts, density or cumulative. Optional, default counts.
+	Opacity    float64        // Fill opacity of bins. Optional, default 1 for a single histogram and 0.5 for multiple.
+	YLim       [2]float64     // Y axis limits. Optional, default auto.
+	Labels     Labels         // Labels for plot and axes. Optional.
+	Style      *Style         // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	XAxis      Axis           // X axis configuration (scale, tick format, grid, ...). Optional.
+	YAxis      Axis           // Y axis configuration (scale, tick format, grid, ...). Optional.
+	Async      bool           // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath   string         // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+	ExportPath string         // File for exporting the data with Ctrl+E, format by extension. Optional, default time-stamped CSV.
+
+	indices  []int
+	names    []string
+	values   [][]float64
+	sorted   []float64
+	edges    []float64
+	counts   [][]float64
+	scale    float64
+	style    *Style
+	renderer plotRenderer
+}
+
+// Initialize the drawer.
+func (h *Histogram) Initialize(w *ecs.World, _ *opengl.Window) {
+	if (h.Observer == nil) == (h.Row == nil) {
+		panic("histogram requires exactly one of Observer and Row")
+	}
+	if len(h.Edges) == 1 {
+		panic("histogram requires at least two edges")
+	}
+	for i := 1; i < len(h.Edges); i++ {
+		if !(h.Edges[i] > h.Edges[i-1]) {
+			panic("histogram edges must be in increasing order")
+		}
+	}
+
+	if h.Row != nil {
+		h.Row.Initialize(w)
+		h.indices = []int{0}
+		h.names = []string{"Values"}
+	} else {
+		h.Observer.Initialize(w)
+		headers := h.Observer.Header()
+		if len(h.Columns) == 0 {
+			h.indices = make([]int, len(headers))
+			for i := range h.indices {
+				h.indices[i] = i
+			}
+		} else {
+			h.indices = make([]int, len(h.Columns))
+			var ok bool
+			for i, col := range h.Columns {
+				h.indices[i], ok = find(headers, col)
+				if !ok {
+					panic(fmt.Sprintf("column '%s' not found", col))
+				}
+			}
+		}
+		h.names = make([]string, len(h.indices))
+		for i, idx := range h.indices {
+			h.names[i] = headers[idx]
+		}
+	}
+
+	h.values = make([][]float64, len(h.indices))
+	h.counts = make([][]float64, len(h.indices))
+	h.edges = append(h.edges[:0], h.Edges...)
+
+	h.scale = calcScaleCorrection()
+	h.style = h.Style.resolve()
+	h.renderer = plotRenderer{}
+}
+
+// Update the drawer.
+func (h *Histogram) Update(w *ecs.World) {
+	if h.Row != nil {
+		h.Row.Update(w)
+	} else {
+		h.Observer.Update(w)
+	}
+	h.renderer.Invalidate()
+}
+
+// UpdateInputs handles input events of the previous frame update.
+func (h *Histogram) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	h.renderer.HandleInputs(win, h.scale)
+	if savePressed(win) {
+		saveWindow(win, h.SavePath, h.scale, h.SaveAs)
+	}
+	if exportPressed(win) {
+		exportWindow(h.ExportPath, h.ExportData)
+	}
+}
+
+// Draw the drawer.
+func (h *Histogram) Draw(w *ecs.World, win *opengl.Window) {
+	h.renderer.Draw(win, h.scale, h.Async, func() *figure {
+		h.updateData(w)
+		return h.buildFigure()
+	})
+}
+
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (h *Histogram) SaveAs(path string, width, height vg.Length) error {
+	return h.renderer.Save(path, width, height)
+}
+
+// ExportData writes the current bins of the plot to a file, with the lower and upper edges of the bins,
+// and the normalized bin heights per column of the observer.
+// The file format is selected by the extension. Supported are csv and json.
+func (h *Histogram) ExportData(path string) error {
+	bins := max(len(h.edges)-1, 0)
+	lower := dataColumn{Name: "Lower", Values: make([]float64, bins)}
+	upper := dataColumn{Name: "Upper", Values: make([]float64, bins)}
+	for i := range bins {
+		lower.Values[i], upper.Values[i] = h.edges[i], h.edges[i+1]
+	}
+	columns := []dataColumn{lower, upper}
+	for i, counts := range h.counts {
+		columns = append(columns, dataColumn{Name: h.names[i], Values: counts})
+	}
+	return exportData(path, columns)
+}
+
+func (h *Histogram) buildFigure() *figure {
+	p := plot.New()
+	setLabels(p, h.Labels, h.style)
+
+	p.X.Tick.Marker = removeLastTicks{}
+	setAxes(p, h.XAxis, h.YAxis, h.style)
+
+	if h.YLim[0] != 0 || h.YLim[1] != 0 {
+		p.Y.Min = h.YLim[0]
+		p.Y.Max = h.YLim[1]
+	}
+
+	p.Legend = newLegend(h.style)
+
+	opacity := h.Opacity
+	if opacity <= 0 {
+		opacity = 1
+		if len(h.counts) > 1 {
+			opacity = 0.5
+		}
+	}
+
+	fig := figure{plot: p}
+	for i, counts := range h.counts {
+		hist := &plotter.Histogram{
+			Bins:      make([]plotter.HistogramBin, len(counts)),
+			FillColor: bandColor(h.style.color(i), opacity),
+			LineStyle: draw.LineStyle{Color: h.style.color(i), Width: h.style.LineWidth},
+			LogY:      h.YAxis.Scale == LogScale,
+		}
+		centers := make(plotter.XYs, len(counts))
+		for j, c := range counts {
+			hist.Bins[j] = plotter.HistogramBin{Min: h.edges[j], Max: h.edges[j+1], Weight: c}
+			centers[j] = plotter.XY{X: (h.edges[j] + h.edges[j+1]) / 2, Y: c}
+		}
+		p.Add(hist)
+		p.Legend.Add(h.names[i], hist)
+		fig.series = append(fig.series, namedSeries{Name: h.names[i], XYs: centers})
+	}
+
+	return &fig
+}
+
+func (h *Histogram) updateData(w *ecs.World) {
+	if h.Row != nil {
+		h.values[0] = append(h.values[0][:0], h.Row.Values(w)...)
+	} else {
+		rows := h.Observer.Values(w)
+		for i, idx := range h.indices {
+			h.values[i] = h.values[i][:0]
+			for _, row := range rows {
+				h.values[i] = append(h.values[i], row[idx])
+			}
+		}
+	}
+
+	if len(h.Edges) == 0 {
+		h.sorted = h.sorted[:0]
+		for _, values := range h.values {
+			for _, v := range values {
+				if !math.IsNaN(v) {
+					h.sorted = append(h.sorted, v)
+				}
+			}
+		}
+		sort.Float64s(h.sorted)
+		h.edges = binEdges(h.sorted, h.Bins, h.Binning, h.Range, h.edges)
+	}
+
+	for i, values := range h.values {
+		var total int
+		h.counts[i], total = binCounts(values, h.edges, h.counts[i])
+		h.Normalize.normalize(h.counts[i], h.edges, total)
+	}
+}
@@ -0,0 +1,109 @@
+package plot_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/ark-pixel/plot"
+	"github.com/mlange-42/ark-pixel/window"
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleHistogram() {
+
+	// Create a new model.
+	app := app.New()
+
+	// Limit the the simulation speed.
+	app.TPS = 30
+
+	// Create a histogram plot.
+	// See below for the implementation of the TableObserver.
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Histogram{
+			Observer: &TableObserver{},
+			Columns:  []string{"A", "C"},           // Optional, defaults to all columns
+			Binning:  plot.FreedmanDiaconisBinning, // Optional, defaults to Sturges' rule
+		}))
+
+	// Add a termination system that ends the simulation.
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+
+	app.Run()
+
+	// Run the simulation.
+	// Due to the use of the OpenGL UI system, the model must be run via [window.Run].
+	// Comment out the code line above, and uncomment the next line to run this example stand-alone.
+
+	// window.Run(app)
+
+	// Output:
+}
+
+func TestHistogram_Row(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Histogram{
+			Row:       &RowObserver{},
+			Bins:      5,
+			Range:     [2]float64{0, 5},
+			Normalize: plot.HistogramDensity,
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestHistogram_Edges(t *testing.T) {
+	hist := plot.Histogram{
+		Observer:  &TableObserver{},
+		Columns:   []string{"A", "B"},
+		Edges:     []float64{0, 0.25, 0.5, 1},
+		Normalize: plot.HistogramCumulative,
+		YAxis:     plot.Axis{Grid: true},
+	}
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&hist))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "histogram.csv")
+	assert.Nil(t, hist.ExportData(path))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.Equal(t, "Lower,Upper,A,B", lines[0])
+	assert.True(t, strings.HasPrefix(lines[3], "0.5,1,1,1"))
+}
+
+func TestHistogram_Panic(t *testing.T) {
+	for _, hist := range []plot.Histogram{
+		{},
+		{Observer: &TableObserver{}, Row: &RowObserver{}},
+		{Observer: &TableObserver{}, Columns: []string{"A", "F"}},
+		{Observer: &TableObserver{}, Edges: []float64{1}},
+		{Observer: &TableObserver{}, Edges: []float64{0, 2, 1}},
+	} {
+		app := app.New()
+		app.TPS = 300
+		app.AddUISystem((&window.Window{}).With(&hist))
+		app.AddSystem(&system.FixedTermination{
+			Steps: 100,
+		})
+		assert.Panics(t, app.Run)
+	}
+}
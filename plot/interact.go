@@ -0,0 +1,319 @@
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	px "github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/gopxl/pixel/v2/ext/text"
+	"github.com/mlange-42/ark-pixel/internal/util"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Zoom factor per mouse wheel step.
+const zoomStep = 0.85
+
+// Minimum size of a zoom box, in window pixels.
+const minZoomBox = 5
+
+// Maximum distance of the crosshair to the nearest data point, in window pixels.
+const crosshairSnap = 30
+
+var (
+	crosshairColor = color.RGBA{80, 80, 80, 160}
+	zoomBoxColor   = color.RGBA{30, 100, 200, 60}
+	tooltipColor   = color.RGBA{255, 255, 255, 230}
+)
+
+// namedSeries is a data series with a name, for finding the nearest point to the crosshair.
+type namedSeries struct {
+	Name   string
+	XYs    plotter.XYs
+	Values plotter.XYs // Original values for display, if XYs were transformed for a secondary axis. Optional.
+}
+
+type dragMode uint8
+
+const (
+	dragNone dragMode = iota
+	dragPan
+	dragBox
+)
+
+// plotView handles interactive zoom, pan and crosshair for gonum plots.
+//
+// Controls:
+//   - Mouse wheel: zoom in and out around the cursor
+//   - Left mouse button drag: pan
+//   - Right mouse button drag: zoom to box
+//   - R: reset to automatic axis limits
+//
+// While the view is zoomed or panned, axis limits are fixed and not updated with the data.
+type plotView struct {
+	zoomed bool
+	xLim   [2]float64 // Current axis limits, if zoomed.
+	yLim   [2]float64
+
+	// Layout of the last figure, for mapping between window and data coordinates.
+	area   vg.Rectangle // Data area in canvas coordinates.
+	xRange [2]float64
+	yRange [2]float64
+	xNorm  plot.Normalizer
+	yNorm  plot.Normalizer
+	series []namedSeries
+	valid  bool
+
+	drag      dragMode
+	dragStart px.Vec
+	dragXLim  [2]float64
+	dragYLim  [2]float64
+	mouse     px.Vec
+
+	drawer *imdraw.IMDraw
+	text   *text.Text
+}
+
+// apply the view limits to a figure, and record its layout.
+func (v *plotView) apply(fig *figure, c draw.Canvas) {
+	p := fig.plot
+	if v.zoomed {
+		p.X.Min, p.X.Max = v.xLim[0], v.xLim[1]
+		p.Y.Min, p.Y.Max = v.yLim[0], v.yLim[1]
+	}
+	v.area = p.DataCanvas(fig.plotCanvas(c)).Rectangle
+	v.xRange = [2]float64{p.X.Min, p.X.Max}
+	v.yRange = [2]float64{p.Y.Min, p.Y.Max}
+	v.xNorm, v.yNorm = p.X.Scale, p.Y.Scale
+	v.series = fig.series
+	v.valid = v.area.Size().X > 0 && v.area.Size().Y > 0 && v.xRange[1] > v.xRange[0] && v.yRange[1] > v.yRange[0]
+}
+
+// HandleInputs processes mouse and keyboard input.
+// Returns whether the view limits changed, so the plot needs to be re-rendered.
+func (v *plotView) HandleInputs(win *opengl.Window, scale float64) bool {
+	v.mouse = win.MousePosition()
+	if !v.valid {
+		return false
+	}
+
+	if win.JustPressed(px.KeyR) {
+		changed := v.zoomed
+		v.zoomed = false
+		v.drag = dragNone
+		return changed
+	}
+
+	inside := v.contains(v.mouse, scale)
+	changed := false
+
+	if scroll := win.MouseScroll().Y; scroll != 0 && inside {
+		v.startZoom()
+		f := math.Pow(zoomStep, scroll)
+		x, y := v.toData(v.mouse, scale)
+		v.xLim = [2]float64{x - (x-v.xLim[0])*f, x + (v.xLim[1]-x)*f}
+		v.yLim = [2]float64{y - (y-v.yLim[0])*f, y + (v.yLim[1]-y)*f}
+		changed = true
+	}
+
+	switch v.drag {
+	case dragNone:
+		if inside && win.JustPressed(px.MouseButtonLeft) {
+			// Limits are only frozen when the mouse moves, so that plain clicks keep following the data.
+			v.drag = dragPan
+			v.dragStart = v.mouse
+			v.dragXLim, v.dragYLim = v.xLim, v.yLim
+		} else if inside && win.JustPressed(px.MouseButtonRight) {
+			v.drag = dragBox
+			v.dragStart = v.mouse
+		}
+	case dragPan:
+		if v.mouse != v.dragStart {
+			if !v.zoomed {
+				v.startZoom()
+				v.dragXLim, v.dragYLim = v.xLim, v.yLim
+			}
+			// Pans linearly in data space, also for non-linear scales.
+			x1, y1 := v.toData(v.dragStart, scale)
+			x2, y2 := v.toData(v.mouse, scale)
+			dx, dy := x2-x1, y2-y1
+			v.xLim = [2]float64{v.dragXLim[0] - dx, v.dragXLim[1] - dx}
+			v.yLim = [2]float64{v.dragYLim[0] - dy, v.dragYLim[1] - dy}
+			changed = true
+		}
+		if !win.Pressed(px.MouseButtonLeft) {
+			v.drag = dragNone
+		}
+	case dragBox:
+		if !win.Pressed(px.MouseButtonRight) {
+			v.drag = dragNone
+			if math.Abs(v.mouse.X-v.dragStart.X) >= minZoomBox && math.Abs(v.mouse.Y-v.dragStart.Y) >= minZoomBox {
+				x1, y1 := v.toData(v.dragStart, scale)
+				x2, y2 := v.toData(v.mouse, scale)
+				v.zoomed = true
+				v.xLim = [2]float64{math.Min(x1, x2), math.Max(x1, x2)}
+				v.yLim = [2]float64{math.Min(y1, y2), math.Max(y1, y2)}
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+// startZoom initializes the view limits from the current axis ranges, if not zoomed yet.
+func (v *plotView) startZoom() {
+	if v.zoomed {
+		return
+	}
+	v.zoomed = true
+	v.xLim, v.yLim = v.xRange, v.yRange
+}
+
+// Draw the crosshair, tooltip and zoom box on top of the plot.
+func (v *plotView) Draw(win *opengl.Window, scale float64) {
+	if !v.valid || !v.contains(v.mouse, scale) && v.drag != dragBox {
+		return
+	}
+	if v.drawer == nil {
+		v.drawer = imdraw.New(nil)
+		v.text = text.New(px.V(0, 0), util.DefaultFont)
+		v.text.Color = color.Black
+	}
+	dr := v.drawer
+	lo, hi := v.toWindow(v.area.Min, scale), v.toWindow(v.area.Max, scale)
+
+	if v.drag == dragBox {
+		dr.Color = zoomBoxColor
+		dr.Push(v.dragStart, px.V(clamp(v.mouse.X, lo.X, hi.X), clamp(v.mouse.Y, lo.Y, hi.Y)))
+		dr.Rectangle(0)
+		dr.Draw(win)
+		dr.Clear()
+		return
+	}
+
+	pos := v.mouse
+	x, y := v.toData(pos, scale)
+	label := fmt.Sprintf("x=%.4g, y=%.4g", x, y)
+	if name, pt, value, ok := v.nearest(pos, scale); ok {
+		pos = v.toWindow(v.toCanvas(pt.X, pt.Y), scale)
+		label = fmt.Sprintf("%s: x=%.4g, y=%.4g", name, value.X, value.Y)
+
+		dr.Color = crosshairColor
+		dr.Push(pos)
+		dr.Circle(4, 1.5)
+	}
+
+	dr.Color = crosshairColor
+	dr.Push(px.V(lo.X, pos.Y), px.V(hi.X, pos.Y))
+	dr.Line(1)
+	dr.Push(px.V(pos.X, lo.Y), px.V(pos.X, hi.Y))
+	dr.Line(1)
+
+	// Tooltip, flipped to the other side of the cursor near the right and top edges.
+	v.text.Clear()
+	bounds := v.text.BoundsOf(label)
+	tx, ty := pos.X+8, pos.Y+8
+	if tx+bounds.W()+4 > hi.X {
+		tx = pos.X - 8 - bounds.W()
+	}
+	if ty+bounds.H()+4 > hi.Y {
+		ty = pos.Y - 8 - bounds.H()
+	}
+	dr.Color = tooltipColor
+	dr.Push(px.V(tx-3, ty-3), px.V(tx+bounds.W()+3, ty+bounds.H()))
+	dr.Rectangle(0)
+
+	v.text.Dot = px.V(tx, ty+bounds.H()-util.DefaultFont.Ascent())
+	_, _ = fmt.Fprint(v.text, label)
+
+	dr.Draw(win)
+	dr.Clear()
+	v.text.Draw(win, px.IM)
+}
+
+// nearest finds the data point nearest to a window position, within the snap distance.
+// Returns the point in plot coordinates, and its original value for display.
+func (v *plotView) nearest(pos px.Vec, scale float64) (string, plotter.XY, plotter.XY, bool) {
+	bestDist := math.Inf(1)
+	var bestName string
+	var bestPt, bestValue plotter.XY
+	for _, s := range v.series {
+		for i, pt := range s.XYs {
+			if pt.X < v.xRange[0] || pt.X > v.xRange[1] || pt.Y < v.yRange[0] || pt.Y > v.yRange[1] {
+				continue
+			}
+			d := v.toWindow(v.toCanvas(pt.X, pt.Y), scale).To(pos).Len()
+			if d < bestDist {
+				bestDist, bestName, bestPt, bestValue = d, s.Name, pt, pt
+				if s.Values != nil {
+					bestValue = s.Values[i]
+				}
+			}
+		}
+	}
+	return bestName, bestPt, bestValue, bestDist <= crosshairSnap
+}
+
+// contains checks whether a window position is inside the data area.
+func (v *plotView) contains(pos px.Vec, scale float64) bool {
+	pt := v.fromWindow(pos, scale)
+	return pt.X >= v.area.Min.X && pt.X <= v.area.Max.X && pt.Y >= v.area.Min.Y && pt.Y <= v.area.Max.Y
+}
+
+// toData converts a window position to data coordinates.
+func (v *plotView) toData(pos px.Vec, scale float64) (float64, float64) {
+	pt := v.fromWindow(pos, scale)
+	size := v.area.Size()
+	x := invertNorm(v.xNorm, v.xRange, float64((pt.X-v.area.Min.X)/size.X))
+	y := invertNorm(v.yNorm, v.yRange, float64((pt.Y-v.area.Min.Y)/size.Y))
+	return x, y
+}
+
+// toCanvas converts data coordinates to canvas coordinates.
+func (v *plotView) toCanvas(x, y float64) vg.Point {
+	size := v.area.Size()
+	return vg.Point{
+		X: v.area.Min.X + vg.Length(v.xNorm.Normalize(v.xRange[0], v.xRange[1], x))*size.X,
+		Y: v.area.Min.Y + vg.Length(v.yNorm.Normalize(v.yRange[0], v.yRange[1], y))*size.Y,
+	}
+}
+
+// invertNorm finds the data value for a normalized position on an axis, by bisection.
+// Works for any monotonic axis scale, including inverted ones.
+// Positions outside [0, 1] are extrapolated linearly.
+func invertNorm(n plot.Normalizer, rng [2]float64, t float64) float64 {
+	if _, ok := n.(plot.LinearScale); ok || t < 0 || t > 1 {
+		if n.Normalize(rng[0], rng[1], rng[1]) < n.Normalize(rng[0], rng[1], rng[0]) {
+			t = 1 - t
+		}
+		return rng[0] + t*(rng[1]-rng[0])
+	}
+	lo, hi := rng[0], rng[1]
+	increasing := n.Normalize(rng[0], rng[1], hi) > n.Normalize(rng[0], rng[1], lo)
+	for range 64 {
+		mid := (lo + hi) / 2
+		if (n.Normalize(rng[0], rng[1], mid) < t) == increasing {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// fromWindow converts a window position to canvas coordinates.
+// Inverse of the sprite placement in [plotRenderer.Draw].
+func (v *plotView) fromWindow(pos px.Vec, scale float64) vg.Point {
+	return vg.Point{X: vg.Length((pos.X - spriteOffset) * scale), Y: vg.Length((pos.Y - spriteOffset) * scale)}
+}
+
+// toWindow converts canvas coordinates to a window position.
+func (v *plotView) toWindow(pt vg.Point, scale float64) px.Vec {
+	return px.V(float64(pt.X)/scale+spriteOffset, float64(pt.Y)/scale+spriteOffset)
+}
@@ -0,0 +1,54 @@
+package plot
+
+import (
+	"testing"
+
+	px "github.com/gopxl/pixel/v2"
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
+)
+
+func TestPlotView(t *testing.T) {
+	xys := plotter.XYs{{X: 0, Y: 0}, {X: 5, Y: 2}, {X: 10, Y: 4}}
+	p := plot.New()
+	lines, err := plotter.NewLine(xys)
+	assert.Nil(t, err)
+	p.Add(lines)
+
+	values := plotter.XYs{{X: 0, Y: 0}, {X: 5, Y: 20}, {X: 10, Y: 40}}
+	fig := figure{plot: p, series: []namedSeries{{Name: "A", XYs: lines.XYs, Values: values}}}
+	canvas := vgimg.New(vg.Points(300), vg.Points(200))
+	scale := 0.75
+
+	view := plotView{}
+	view.apply(&fig, draw.New(canvas))
+	assert.True(t, view.valid)
+	assert.Equal(t, [2]float64{0, 10}, view.xRange)
+	assert.Equal(t, [2]float64{0, 4}, view.yRange)
+
+	pos := view.toWindow(view.toCanvas(5, 2), scale)
+	x, y := view.toData(pos, scale)
+	assert.InDelta(t, 5, x, 1e-9)
+	assert.InDelta(t, 2, y, 1e-9)
+	assert.True(t, view.contains(pos, scale))
+	assert.False(t, view.contains(px.V(0, 0), scale))
+
+	name, pt, value, ok := view.nearest(pos.Add(px.V(3, 3)), scale)
+	assert.True(t, ok)
+	assert.Equal(t, "A", name)
+	assert.Equal(t, plotter.XY{X: 5, Y: 2}, pt)
+	assert.Equal(t, plotter.XY{X: 5, Y: 20}, value)
+
+	view.startZoom()
+	view.xLim = [2]float64{2, 4}
+	view.apply(&fig, draw.New(canvas))
+	assert.Equal(t, [2]float64{2, 4}, view.xRange)
+	assert.Equal(t, [2]float64{0, 4}, view.yRange)
+
+	_, _, _, ok = view.nearest(pos, scale)
+	assert.False(t, ok)
+}
@@ -2,39 +2,51 @@ package plot
 
 import (
 	"fmt"
-	"image/color"
 	"math"
 
-	pixel "github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/mlange-42/ark-tools/observer"
 	"github.com/mlange-42/ark/ecs"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
-	"gonum.org/v1/plot/vg/draw"
-	"gonum.org/v1/plot/vg/vgimg"
 )
 
 // Lines plot drawer.
 //
 // Creates a line series per column of the observer.
 // Replaces the complete data by the table provided by the observer on every update.
-// Particularly useful for live histograms.
+// Particularly useful for live histograms of pre-binned data. For binning raw values, see [Histogram].
 type Lines struct {
-	Observer observer.Table // Observer providing a data series for lines.
-	X        string         // X column name. Optional. Defaults to row index.
-	Y        []string       // Y column names. Optional. Defaults to all but X column.
-	XLim     [2]float64     // X axis limits. Optional, default auto.
-	YLim     [2]float64     // Y axis limits. Optional, default auto.
-	Labels   Labels         // Labels for plot and axes. Optional.
+	Observer   observer.Table // Observer providing a data series for lines.
+	X          string         // X column name. Optional. Defaults to row index.
+	Y          []string       // Y column names. Optional. Defaults to all but X column.
+	Y2         []string       // Y column names to plot against a secondary Y axis on the right. Must be among the Y columns. Optional.
+	Bands      []Band         // Shaded bands around lines, e.g. for uncertainty ranges. Optional.
+	XLim       [2]float64     // X axis limits. Optional, default auto.
+	YLim       [2]float64     // Y axis limits. Optional, default auto.
+	Labels     Labels         // Labels for plot and axes. Optional.
+	Style      *Style         // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	XAxis      Axis           // X axis configuration (scale, tick format, grid, ...). Optional.
+	YAxis      Axis           // Y axis configuration (scale, tick format, grid, ...). Optional.
+	Y2Axis     Axis           // Secondary Y axis configuration. Only the tick format is used, the scale is always linear. Optional.
+	Async      bool           // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath   string         // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+	ExportPath string         // File for exporting the data with Ctrl+E, format by extension. Optional, default time-stamped CSV.
 
 	xIndex   int
 	yIndices []int
-
-	headers []string
-	series  []plotter.XYs
-	scale   float64
+	y2       []bool
+	bands    []bandColumns
+	bandOf   []int
+	shaded   []*bandPlotter
+
+	headers  []string
+	series   []plotter.XYs
+	columns  []dataColumn
+	scale    float64
+	style    *Style
+	renderer plotRenderer
 }
 
 // Initialize the drawer.
@@ -44,6 +56,7 @@ func (l *Lines) Initialize(w *ecs.World, _ *opengl.Window) {
 	l.headers = l.Observer.Header()
 
 	l.scale = calcScaleCorrection()
+	l.style = l.Style.resolve()
 
 	var ok bool
 	if l.X == "" {
@@ -55,10 +68,12 @@ func (l *Lines) Initialize(w *ecs.World, _ *opengl.Window) {
 		}
 	}
 
+	l.bands = resolveBands(l.headers, l.Bands)
+
 	if len(l.Y) == 0 {
 		l.yIndices = make([]int, 0, len(l.headers))
 		for i := range l.headers {
-			if i != l.xIndex {
+			if i != l.xIndex && !isBandBound(l.bands, i) {
 				l.yIndices = append(l.yIndices, i)
 			}
 		}
@@ -73,29 +88,72 @@ func (l *Lines) Initialize(w *ecs.World, _ *opengl.Window) {
 	}
 
 	l.series = make([]plotter.XYs, len(l.yIndices))
+	l.y2 = secondaryFlags(l.headers, l.yIndices, l.Y2)
+
+	l.bandOf = bandsByLine(l.headers, l.yIndices, l.bands)
+	l.shaded = make([]*bandPlotter, len(l.yIndices))
+	for i, b := range l.bandOf {
+		if b >= 0 {
+			l.shaded[i] = &bandPlotter{Color: bandColor(l.style.color(i), l.bands[b].Opacity)}
+		}
+	}
+
+	// Raw data columns for export, starting with X.
+	l.columns = make([]dataColumn, len(l.yIndices)+1)
+	l.columns[0].Name = "Index"
+	if l.xIndex >= 0 {
+		l.columns[0].Name = l.headers[l.xIndex]
+	}
+	for i, idx := range l.yIndices {
+		l.columns[i+1].Name = l.headers[idx]
+	}
+
+	l.renderer = plotRenderer{}
 }
 
 // Update the drawer.
 func (l *Lines) Update(w *ecs.World) {
 	l.Observer.Update(w)
+	l.renderer.Invalidate()
 }
 
 // UpdateInputs handles input events of the previous frame update.
-func (l *Lines) UpdateInputs(_ *ecs.World, _ *opengl.Window) {}
+func (l *Lines) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	l.renderer.HandleInputs(win, l.scale)
+	if savePressed(win) {
+		saveWindow(win, l.SavePath, l.scale, l.SaveAs)
+	}
+	if exportPressed(win) {
+		exportWindow(l.ExportPath, l.ExportData)
+	}
+}
 
 // Draw the drawer.
 func (l *Lines) Draw(w *ecs.World, win *opengl.Window) {
-	width := win.Canvas().Bounds().W()
-	height := win.Canvas().Bounds().H()
+	l.renderer.Draw(win, l.scale, l.Async, func() *figure {
+		l.updateData(w)
+		return l.buildFigure()
+	})
+}
 
-	l.updateData(w)
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (l *Lines) SaveAs(path string, width, height vg.Length) error {
+	return l.renderer.Save(path, width, height)
+}
 
-	c := vgimg.New(vg.Points(width*l.scale)-10, vg.Points(height*l.scale)-10)
+// ExportData writes the current data of the plot to a file, with column headers from the observer.
+// The file format is selected by the extension. Supported are csv and json.
+func (l *Lines) ExportData(path string) error {
+	return exportData(path, l.columns)
+}
 
+func (l *Lines) buildFigure() *figure {
 	p := plot.New()
-	setLabels(p, l.Labels)
+	setLabels(p, l.Labels, l.style)
 
 	p.X.Tick.Marker = removeLastTicks{}
+	setAxes(p, l.XAxis, l.YAxis, l.style)
 
 	if l.YLim[0] != 0 || l.YLim[1] != 0 {
 		p.Y.Min = l.YLim[0]
@@ -107,28 +165,23 @@ func (l *Lines) Draw(w *ecs.World, win *opengl.Window) {
 		p.X.Max = l.XLim[1]
 	}
 
-	p.Legend = plot.NewLegend()
-	p.Legend.TextStyle.Font.Variant = "Mono"
+	fig := figure{plot: p}
+	fig.secondary = secondaryFor(l.series, l.y2, l.Labels.Y2, l.Y2Axis)
 
-	for i := range l.series {
-		idx := l.yIndices[i]
-		lines, err := plotter.NewLine(l.series[i])
-		if err != nil {
-			panic(err)
+	p.Legend = newLegend(l.style)
+
+	bands := make([]*bandPlotter, len(l.yIndices))
+	for i, b := range l.shaded {
+		if b != nil {
+			bands[i] = newBandPlotter(b.Lower, b.Upper, b.Color)
+			addBand(&fig, bands[i], l.y2[i])
 		}
-		lines.Color = defaultColors[i%len(defaultColors)]
-		p.Add(lines)
-		p.Legend.Add(l.headers[idx], lines)
+	}
+	for i, idx := range l.yIndices {
+		fig.series = append(fig.series, addLine(&fig, l.series[i], l.headers[idx], l.y2[i], l.style.lineStyle(i), bands[i]))
 	}
 
-	win.Clear(color.White)
-	p.Draw(draw.New(c))
-
-	img := c.Image()
-	picture := pixel.PictureDataFromImage(img)
-
-	sprite := pixel.NewSprite(picture, picture.Bounds())
-	sprite.Draw(win, pixel.IM.Moved(pixel.V(picture.Rect.W()/2.0+5, picture.Rect.H()/2.0+5)))
+	return &fig
 }
 
 func (l *Lines) updateData(w *ecs.World) {
@@ -136,17 +189,37 @@ func (l *Lines) updateData(w *ecs.World) {
 	xi := l.xIndex
 	yis := l.yIndices
 
+	xs := l.columns[0].Values[:0]
+	for j, row := range data {
+		x := float64(j)
+		if xi >= 0 {
+			x = row[xi]
+		}
+		xs = append(xs, x)
+	}
+	l.columns[0].Values = xs
+
 	for i, idx := range yis {
 		l.series[i] = l.series[i][:0]
+		ys := l.columns[i+1].Values[:0]
 		for j, row := range data {
-			x := float64(j)
-			if xi >= 0 {
-				x = row[xi]
-			}
+			ys = append(ys, row[idx])
 			if math.IsNaN(row[idx]) {
 				continue
 			}
-			l.series[i] = append(l.series[i], plotter.XY{X: x, Y: row[idx]})
+			l.series[i] = append(l.series[i], plotter.XY{X: xs[j], Y: row[idx]})
+		}
+		l.columns[i+1].Values = ys
+	}
+
+	for i, b := range l.bandOf {
+		if b < 0 {
+			continue
 		}
+		band := l.shaded[i]
+		band.Lower, band.Upper = l.bands[b].bounds(len(data),
+			func(row int) float64 { return xs[row] },
+			func(row, col int) float64 { return data[row][col] },
+			band.Lower, band.Upper)
 	}
 }
@@ -1,6 +1,7 @@
 package plot_test
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/mlange-42/ark-pixel/plot"
@@ -59,6 +60,77 @@ func TestLines(t *testing.T) {
 	app.Run()
 }
 
+func TestLines_Axes(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Lines{
+			Observer: &TableObserver{},
+			XAxis:    plot.Axis{Scale: plot.SymLogScale, Format: plot.SIFormat, Grid: true},
+			YAxis:    plot.Axis{Scale: plot.LogScale, Grid: true, Invert: true},
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestLines_Y2(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Lines{
+			Observer: &TableObserver{},
+			X:        "X",
+			Y2:       []string{"C"},
+			Labels:   plot.Labels{Y: "A, B", Y2: "C"},
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestLines_Bands(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Lines{
+			Observer: &TableObserver{},
+			X:        "X",
+			Bands:    []plot.Band{{Line: "A", Spread: "C"}},
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestLines_ExportData(t *testing.T) {
+	lines := plot.Lines{
+		Observer: &TableObserver{},
+		X:        "X",
+	}
+
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&lines))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	dir := t.TempDir()
+	assert.Nil(t, lines.ExportData(filepath.Join(dir, "data.csv")))
+	assert.FileExists(t, filepath.Join(dir, "data.csv"))
+	assert.Nil(t, lines.ExportData(filepath.Join(dir, "data.json")))
+	assert.FileExists(t, filepath.Join(dir, "data.json"))
+}
+
 func TestLines_PanicX(t *testing.T) {
 	app := app.New()
 	app.AddUISystem((&window.Window{}).
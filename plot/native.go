@@ -0,0 +1,331 @@
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	px "github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/gopxl/pixel/v2/ext/text"
+	"github.com/mlange-42/ark-pixel/internal/util"
+	"gonum.org/v1/plot/plotter"
+)
+
+// Desired number of ticks per axis for native plots.
+const nativeTicks = 6
+
+// nativePlot draws line plots directly with imdraw and text,
+// without rasterization through gonum.
+//
+// Styling is simpler than for gonum plots, but drawing is much faster.
+// Uses the colors and the line width of the style, but ignores fonts, dashes and markers.
+type nativePlot struct {
+	drawer  imdraw.IMDraw
+	text    *text.Text
+	yLabel  *text.Text
+	y2Label *text.Text
+	style   *Style
+	ranged  []plotter.XYs // Buffer for calculating data ranges.
+}
+
+func newNativePlot(style *Style) nativePlot {
+	p := nativePlot{
+		drawer:  *imdraw.New(nil),
+		text:    text.New(px.V(0, 0), util.DefaultFont),
+		yLabel:  text.New(px.V(0, 0), util.DefaultFont),
+		y2Label: text.New(px.V(0, 0), util.DefaultFont),
+		style:   style,
+	}
+	p.text.Color = style.Foreground
+	p.yLabel.Color = style.Foreground
+	p.y2Label.Color = style.Foreground
+	return p
+}
+
+// Draw line series to the window, with axes, ticks, grid lines and a legend.
+// Series and names must be of the same length.
+// Bands are optional shaded areas per series, and may be nil or contain nil entries.
+// If secondary is not nil, a secondary Y axis is drawn on the right,
+// and series and bands are expected to be transformed to the primary axis already.
+// Vertical and horizontal marker lines are optional. Horizontal lines extend the Y range.
+func (p *nativePlot) Draw(win *opengl.Window, labels Labels, names []string, series []plotter.XYs,
+	bands []*bandPlotter, secondary *secondaryAxis, vLines, hLines []Marker) {
+	width := win.Canvas().Bounds().W()
+	height := win.Canvas().Bounds().H()
+	lineHeight := util.DefaultFont.LineHeight()
+
+	win.Clear(p.style.Background)
+	p.text.Clear()
+	p.yLabel.Clear()
+	p.y2Label.Clear()
+
+	// Data area, leaving margins for tick labels and axis labels.
+	left, bottom := 70.0, 30.0
+	right, top := width-15, height-15
+	if labels.Title != "" {
+		top -= 2 * lineHeight
+	}
+	if labels.X != "" {
+		bottom += 1.5 * lineHeight
+	}
+	if labels.Y != "" {
+		left += 1.5 * lineHeight
+	}
+	if secondary != nil {
+		right -= 55
+		if labels.Y2 != "" {
+			right -= 1.5 * lineHeight
+		}
+	}
+	if right-left < 10 || top-bottom < 10 {
+		return
+	}
+
+	p.ranged = append(p.ranged[:0], series...)
+	for _, b := range bands {
+		if b != nil {
+			p.ranged = append(p.ranged, b.Lower, b.Upper)
+		}
+	}
+	xMin, xMax, yMin, yMax := seriesRange(p.ranged)
+	if len(hLines) > 0 {
+		yMin, yMax = markerRange(hLines, yMin, yMax)
+	}
+	xTicks := calcTicks(xMin, xMax, nativeTicks)
+	yTicks := calcTicks(yMin, yMax, nativeTicks)
+
+	trX := func(x float64) float64 { return left + (x-xMin)/(xMax-xMin)*(right-left) }
+	trY := func(y float64) float64 { return bottom + (y-yMin)/(yMax-yMin)*(top-bottom) }
+
+	dr := &p.drawer
+
+	// Grid lines and tick labels.
+	dr.Color = p.style.GridColor
+	for _, x := range xTicks.Values {
+		dr.Push(px.V(trX(x), bottom), px.V(trX(x), top))
+		dr.Line(1)
+		p.textAt(fmt.Sprintf("%.*f", xTicks.Decimals, x), trX(x), bottom-lineHeight-4, px.V(0.5, 0))
+	}
+	for _, y := range yTicks.Values {
+		dr.Push(px.V(left, trY(y)), px.V(right, trY(y)))
+		dr.Line(1)
+		p.textAt(fmt.Sprintf("%.*f", yTicks.Decimals, y), left-6, trY(y)-lineHeight/3, px.V(1, 0))
+	}
+	var y2Ticks ticks
+	if secondary != nil {
+		y2Ticks = calcTicks(yMin*secondary.Scale+secondary.Offset, yMax*secondary.Scale+secondary.Offset, nativeTicks)
+		for i, v := range y2Ticks.Values {
+			y2Ticks.Values[i] = (v - secondary.Offset) / secondary.Scale
+			p.textAt(fmt.Sprintf("%.*f", y2Ticks.Decimals, v), right+6, trY(y2Ticks.Values[i])-lineHeight/3, px.V(0, 0))
+		}
+	}
+
+	// Bands, as a quad per pair of rows.
+	for _, b := range bands {
+		if b == nil {
+			continue
+		}
+		dr.Color = b.Color
+		for i := 1; i < len(b.Lower); i++ {
+			l0, l1, u0, u1 := b.Lower[i-1], b.Lower[i], b.Upper[i-1], b.Upper[i]
+			if math.IsNaN(l0.Y) || math.IsNaN(l1.Y) || math.IsNaN(u0.Y) || math.IsNaN(u1.Y) {
+				continue
+			}
+			dr.Push(px.V(trX(l0.X), trY(l0.Y)), px.V(trX(l1.X), trY(l1.Y)), px.V(trX(u1.X), trY(u1.Y)), px.V(trX(u0.X), trY(u0.Y)))
+			dr.Polygon(0)
+		}
+	}
+
+	// Data.
+	lineWidth := 1.5 * float64(p.style.LineWidth)
+	for i, s := range series {
+		dr.Color = p.style.color(i)
+		for _, pt := range s {
+			if math.IsNaN(pt.Y) {
+				dr.Line(lineWidth)
+				continue
+			}
+			dr.Push(px.V(trX(pt.X), trY(pt.Y)))
+		}
+		dr.Line(lineWidth)
+	}
+
+	p.drawMarkers(vLines, false, xMin, xMax, trX, bottom, top)
+	p.drawMarkers(hLines, true, yMin, yMax, trY, left, right)
+
+	// Axes box and tick marks.
+	dr.Color = p.style.Foreground
+	dr.Push(px.V(left, bottom), px.V(right, top))
+	dr.Rectangle(1)
+	for _, x := range xTicks.Values {
+		dr.Push(px.V(trX(x), bottom), px.V(trX(x), bottom-4))
+		dr.Line(1)
+	}
+	for _, y := range yTicks.Values {
+		dr.Push(px.V(left, trY(y)), px.V(left-4, trY(y)))
+		dr.Line(1)
+	}
+	for _, y := range y2Ticks.Values {
+		dr.Push(px.V(right, trY(y)), px.V(right+4, trY(y)))
+		dr.Line(1)
+	}
+
+	p.drawLegend(left, top, right, names)
+
+	// Plot and axis labels.
+	if labels.Title != "" {
+		p.textAt(labels.Title, (left+right)/2, height-15-lineHeight, px.V(0.5, 0))
+	}
+	if labels.X != "" {
+		p.textAt(labels.X, (left+right)/2, 5, px.V(0.5, 0))
+	}
+	if labels.Y != "" {
+		_, _ = fmt.Fprint(p.yLabel, labels.Y)
+	}
+	if secondary != nil && labels.Y2 != "" {
+		_, _ = fmt.Fprint(p.y2Label, labels.Y2)
+	}
+
+	dr.Draw(win)
+	dr.Clear()
+
+	p.text.Draw(win, px.IM)
+	if labels.Y != "" {
+		w := p.yLabel.Bounds().W()
+		p.yLabel.Draw(win, px.IM.Rotated(px.ZV, math.Pi/2).Moved(px.V(5+lineHeight, (bottom+top+w)/2)))
+	}
+	if secondary != nil && labels.Y2 != "" {
+		w := p.y2Label.Bounds().W()
+		p.y2Label.Draw(win, px.IM.Rotated(px.ZV, math.Pi/2).Moved(px.V(width-5, (bottom+top+w)/2)))
+	}
+}
+
+// drawLegend draws the legend into the top right corner of the data area.
+func (p *nativePlot) drawLegend(left, top, right float64, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	lineHeight := util.DefaultFont.LineHeight()
+	maxWidth := 0.0
+	for _, name := range names {
+		maxWidth = math.Max(maxWidth, p.text.BoundsOf(name).W())
+	}
+	w := maxWidth + 40
+	h := float64(len(names))*lineHeight + 8
+	x0, y0 := right-w-8, top-h-8
+	if x0 < left {
+		return
+	}
+
+	dr := &p.drawer
+	bg := color.NRGBAModel.Convert(p.style.Background).(color.NRGBA)
+	bg.A = 220
+	dr.Color = bg
+	dr.Push(px.V(x0, y0), px.V(x0+w, y0+h))
+	dr.Rectangle(0)
+
+	for i, name := range names {
+		y := y0 + h - 4 - (float64(i)+0.5)*lineHeight
+		dr.Color = p.style.color(i)
+		dr.Push(px.V(x0+6, y), px.V(x0+28, y))
+		dr.Line(2)
+		p.textAt(name, x0+34, y-lineHeight/3, px.V(0, 0))
+	}
+}
+
+// drawMarkers draws marker lines with labels, skipping lines outside the range [lo, hi].
+// Tr transforms marker values to window coordinates, and from and to are the extent of the lines.
+func (p *nativePlot) drawMarkers(markers []Marker, horizontal bool, lo, hi float64, tr func(float64) float64, from, to float64) {
+	lineHeight := util.DefaultFont.LineHeight()
+	dr := &p.drawer
+	for _, m := range markers {
+		if m.Value < lo || m.Value > hi {
+			continue
+		}
+		col := m.Color
+		if col == nil {
+			col = p.style.Foreground
+		}
+		dr.Color = col
+		v := tr(m.Value)
+		if horizontal {
+			dr.Push(px.V(from, v), px.V(to, v))
+		} else {
+			dr.Push(px.V(v, from), px.V(v, to))
+		}
+		dr.Line(float64(p.style.LineWidth))
+		if m.Label == "" {
+			continue
+		}
+		p.text.Color = col
+		if horizontal {
+			p.textAt(m.Label, to-4, v+3, px.V(1, 0))
+		} else {
+			p.textAt(m.Label, v+4, to-lineHeight-2, px.V(0, 0))
+		}
+		p.text.Color = p.style.Foreground
+	}
+}
+
+// textAt writes text to the given position.
+// Align gives the relative anchor of the text, like (0.5, 0) for bottom center.
+func (p *nativePlot) textAt(s string, x, y float64, align px.Vec) {
+	bounds := p.text.BoundsOf(s)
+	p.text.Dot = px.V(x-align.X*bounds.W(), y-align.Y*bounds.H())
+	_, _ = fmt.Fprint(p.text, s)
+}
+
+// seriesRange calculates the data range of all series, ignoring NaN values.
+// Expands empty ranges to avoid division by zero.
+func seriesRange(series []plotter.XYs) (xMin, xMax, yMin, yMax float64) {
+	xMin, yMin = math.Inf(1), math.Inf(1)
+	xMax, yMax = math.Inf(-1), math.Inf(-1)
+	for _, s := range series {
+		for _, pt := range s {
+			if math.IsNaN(pt.Y) {
+				continue
+			}
+			xMin, xMax = math.Min(xMin, pt.X), math.Max(xMax, pt.X)
+			yMin, yMax = math.Min(yMin, pt.Y), math.Max(yMax, pt.Y)
+		}
+	}
+	if math.IsInf(xMin, 1) {
+		return 0, 1, 0, 1
+	}
+	if xMax <= xMin {
+		xMin, xMax = xMin-0.5, xMax+0.5
+	}
+	if yMax <= yMin {
+		yMin, yMax = yMin-0.5, yMax+0.5
+	}
+	return
+}
+
+// markerRange extends the range [lo, hi] by the values of the markers.
+func markerRange(markers []Marker, lo, hi float64) (float64, float64) {
+	for _, m := range markers {
+		lo, hi = math.Min(lo, m.Value), math.Max(hi, m.Value)
+	}
+	return lo, hi
+}
+
+// ticks of an axis.
+type ticks struct {
+	Values   []float64
+	Decimals int // Number of decimal places required to distinguish tick labels.
+}
+
+// calcTicks calculates axis ticks at "nice" values in the given range.
+func calcTicks(lo, hi float64, desired int) ticks {
+	step := util.CalcTicksStep(hi-lo, desired)
+	decimals := max(0, int(-math.Floor(math.Log10(step))))
+
+	t := ticks{Decimals: decimals}
+	// Multiply instead of accumulating steps, to avoid rounding errors like "-0.0".
+	for i := math.Ceil(lo / step); i*step <= hi+step*1e-9; i++ {
+		t.Values = append(t.Values, i*step)
+	}
+	return t
+}
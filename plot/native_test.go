@@ -0,0 +1,36 @@
+package plot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestCalcTicks(t *testing.T) {
+	ticks := calcTicks(0, 100, 6)
+	assert.Equal(t, []float64{0, 20, 40, 60, 80, 100}, ticks.Values)
+	assert.Equal(t, 0, ticks.Decimals)
+
+	ticks = calcTicks(-0.25, 0.3, 6)
+	assert.InDeltaSlice(t, []float64{-0.2, 0, 0.2}, ticks.Values, 1e-12)
+
+	ticks = calcTicks(-0.2, 0.3, 6)
+	assert.InDeltaSlice(t, []float64{-0.2, -0.1, 0, 0.1, 0.2, 0.3}, ticks.Values, 1e-12)
+	assert.Equal(t, 1, ticks.Decimals)
+}
+
+func TestSeriesRange(t *testing.T) {
+	xMin, xMax, yMin, yMax := seriesRange([]plotter.XYs{
+		{{X: 0, Y: 1}, {X: 1, Y: math.NaN()}, {X: 2, Y: 3}},
+		{{X: -1, Y: 2}},
+	})
+	assert.Equal(t, []float64{-1, 2, 1, 3}, []float64{xMin, xMax, yMin, yMax})
+
+	xMin, xMax, yMin, yMax = seriesRange([]plotter.XYs{{{X: 1, Y: 1}}})
+	assert.Equal(t, []float64{0.5, 1.5, 0.5, 1.5}, []float64{xMin, xMax, yMin, yMax})
+
+	xMin, xMax, yMin, yMax = seriesRange([]plotter.XYs{{}})
+	assert.Equal(t, []float64{0, 1, 0, 1}, []float64{xMin, xMax, yMin, yMax})
+}
@@ -0,0 +1,165 @@
+package plot
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+
+	"github.com/mazznoer/colorgrad"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Default number of colors for palettes created from gradients.
+const defaultPaletteColors = 64
+
+// Width of the color bar attached to a plot, in points.
+const colorBarWidth = 80
+
+var namedGradients = map[string]func() colorgrad.Gradient{
+	// Sequential, perceptually uniform
+	"viridis": colorgrad.Viridis,
+	"magma":   colorgrad.Magma,
+	"inferno": colorgrad.Inferno,
+	"plasma":  colorgrad.Plasma,
+	"cividis": colorgrad.Cividis,
+	"turbo":   colorgrad.Turbo,
+	// Sequential, single hue
+	"greys":   colorgrad.Greys,
+	"blues":   colorgrad.Blues,
+	"greens":  colorgrad.Greens,
+	"oranges": colorgrad.Oranges,
+	"reds":    colorgrad.Reds,
+	"purples": colorgrad.Purples,
+	// Sequential, multi hue
+	"ylgn":   colorgrad.YlGn,
+	"ylorrd": colorgrad.YlOrRd,
+	"warm":   colorgrad.Warm,
+	"cool":   colorgrad.Cool,
+	// Diverging
+	"diverging": colorgrad.RdBu,
+	"rdbu":      colorgrad.RdBu,
+	"rdylbu":    colorgrad.RdYlBu,
+	"rdylgn":    colorgrad.RdYlGn,
+	"brbg":      colorgrad.BrBG,
+	"piyg":      colorgrad.PiYG,
+	"spectral":  colorgrad.Spectral,
+	// Cyclic
+	"rainbow": colorgrad.Rainbow,
+	"sinebow": colorgrad.Sinebow,
+}
+
+// NamedPalette creates one of the built-in palettes, with the given number of colors.
+// Uses a default number of colors if colors is zero or negative.
+// Panics if there is no palette with the given name.
+//
+// See [PaletteNames] for the available palettes.
+// Most of them are bridged from [github.com/mazznoer/colorgrad].
+// Besides these, "heat" refers to gonum's [palette.Heat].
+func NamedPalette(name string, colors int) palette.Palette {
+	if colors <= 0 {
+		colors = defaultPaletteColors
+	}
+	if name == "heat" {
+		return palette.Heat(colors, 1)
+	}
+	grad, ok := namedGradients[name]
+	if !ok {
+		panic(fmt.Sprintf("palette '%s' not found", name))
+	}
+	return GradientPalette(grad(), colors)
+}
+
+// PaletteNames returns the names of all built-in palettes, in alphabetical order.
+func PaletteNames() []string {
+	names := make([]string, 0, len(namedGradients)+1)
+	names = append(names, "heat")
+	for name := range namedGradients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GradientPalette creates a palette with the given number of colors from a [colorgrad.Gradient].
+// Uses a default number of colors if colors is zero or negative.
+func GradientPalette(grad colorgrad.Gradient, colors int) palette.Palette {
+	if colors <= 0 {
+		colors = defaultPaletteColors
+	}
+	return gradientPalette(grad.Colors(uint(colors)))
+}
+
+type gradientPalette []colorgrad.Color
+
+// Colors implements the palette.Palette interface.
+func (p gradientPalette) Colors() []color.Color {
+	cols := make([]color.Color, len(p))
+	for i, c := range p {
+		r, g, b, a := c.RGBA255()
+		cols[i] = color.NRGBA{R: r, G: g, B: b, A: a}
+	}
+	return cols
+}
+
+// defaultPalette is used by drawers if no palette is given.
+func defaultPalette() palette.Palette {
+	return NamedPalette("viridis", defaultPaletteColors)
+}
+
+// drawColorBar draws a vertical color bar for the given colors into the right margin of the canvas,
+// and returns the remaining canvas for the actual plot.
+// The color bar is aligned to the data area of plot p when drawn into the returned canvas.
+func drawColorBar(c draw.Canvas, p *plot.Plot, cols []color.Color, min, max float64) draw.Canvas {
+	plotCanvas := draw.Crop(c, 0, -colorBarWidth, 0, 0)
+	if len(cols) == 0 || !(max > min) {
+		return plotCanvas
+	}
+	dataCanvas := p.DataCanvas(plotCanvas)
+
+	cb := plot.New()
+	cb.HideX()
+	cb.BackgroundColor = nil
+	cb.Y.Tick.Label.Font.Size = p.Y.Tick.Label.Font.Size
+	cb.Y.Tick.Label.Font.Variant = p.Y.Tick.Label.Font.Variant
+	cb.Y.Tick.Label.Color = p.Y.Tick.Label.Color
+	cb.Y.Tick.Color = p.Y.Tick.Color
+	cb.Y.Color = p.Y.Color
+	cb.Add(&colorBarStrip{Colors: cols, Min: min, Max: max})
+
+	barCanvas := draw.Crop(c, c.Max.X-c.Min.X-colorBarWidth, 0, 0, 0)
+	barCanvas.Min.Y = dataCanvas.Min.Y
+	barCanvas.Max.Y = dataCanvas.Max.Y
+	barCanvas.Min.X += vg.Points(5)
+	cb.Draw(barCanvas)
+
+	return plotCanvas
+}
+
+// colorBarStrip is a plotter for the color strip of a color bar.
+//
+// Draws a rectangle per color rather than an image, as not all vector formats support images.
+type colorBarStrip struct {
+	Colors []color.Color
+	Min    float64
+	Max    float64
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (s *colorBarStrip) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	step := (s.Max - s.Min) / float64(len(s.Colors))
+	x0, x1 := trX(0), trX(1)
+	for i, col := range s.Colors {
+		// Overlap by a fraction of a point to avoid anti-aliasing seams.
+		y0, y1 := trY(s.Min+float64(i)*step), trY(s.Min+float64(i+1)*step)+vg.Points(0.5)
+		c.FillPolygon(col, c.ClipPolygonY([]vg.Point{{X: x0, Y: y0}, {X: x1, Y: y0}, {X: x1, Y: y1}, {X: x0, Y: y1}}))
+	}
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+func (s *colorBarStrip) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return 0, 1, s.Min, s.Max
+}
@@ -0,0 +1,28 @@
+package plot
+
+import (
+	"testing"
+
+	"github.com/mazznoer/colorgrad"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamedPalette(t *testing.T) {
+	for _, name := range PaletteNames() {
+		pal := NamedPalette(name, 16)
+		assert.Equal(t, 16, len(pal.Colors()), name)
+	}
+
+	assert.Equal(t, defaultPaletteColors, len(NamedPalette("viridis", 0).Colors()))
+	assert.Panics(t, func() { NamedPalette("foo", 16) })
+}
+
+func TestGradientPalette(t *testing.T) {
+	pal := GradientPalette(colorgrad.Greys(), 3)
+	cols := pal.Colors()
+	assert.Equal(t, 3, len(cols))
+
+	r0, _, _, _ := cols[0].RGBA()
+	r2, _, _, _ := cols[2].RGBA()
+	assert.Greater(t, r0, r2)
+}
@@ -0,0 +1,165 @@
+package plot
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark/ecs"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Pie plot drawer.
+//
+// Creates a slice per column of the observer, as a pie or donut chart.
+// Particularly useful for quick overviews of compositions.
+//
+// Column names are shown in the legend, and slices are labeled with their share in percent, or with their values.
+// Colors are bound to columns, so that they are stable across frames even if some slices are empty.
+// Values that are not positive, including NaN, are left out.
+type Pie struct {
+	Observer    observer.Row           // Observer providing a data row per update.
+	Columns     []string               // Columns to show, by name. Optional, default all.
+	Colors      []color.Color          // Colors per column, cycled. Optional, default the style's colors.
+	Donut       float64                // Inner radius of a donut chart, as a fraction of the outer radius. Optional, default 0 (pie).
+	ValueLabels bool                   // Labels slices with their values instead of percentages. Optional.
+	Format      TickFormat             // Number format of value labels. Optional, default plain numbers.
+	Formatter   func(v float64) string // Custom number formatter for value labels. Optional, overrides Format.
+	HideLabels  bool                   // Hides the labels of slices. Optional.
+	HideLegend  bool                   // Hides the legend with the column names. Optional.
+	Labels      Labels                 // Labels for plot and axes. Only the title is used. Optional.
+	Style       *Style                 // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	Async       bool                   // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath    string                 // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+	ExportPath  string                 // File for exporting the data with Ctrl+E, format by extension. Optional, default time-stamped CSV.
+
+	indices  []int
+	headers  []string
+	values   []float64
+	scale    float64
+	style    *Style
+	format   func(float64) string
+	renderer plotRenderer
+}
+
+// Initialize the drawer.
+func (p *Pie) Initialize(w *ecs.World, _ *opengl.Window) {
+	p.Observer.Initialize(w)
+
+	headers := p.Observer.Header()
+
+	if len(p.Columns) == 0 {
+		p.indices = make([]int, len(headers))
+		for i := range p.indices {
+			p.indices[i] = i
+		}
+	} else {
+		p.indices = make([]int, len(p.Columns))
+		var ok bool
+		for i, col := range p.Columns {
+			p.indices[i], ok = find(headers, col)
+			if !ok {
+				panic(fmt.Sprintf("column '%s' not found", col))
+			}
+		}
+	}
+	if p.Donut < 0 || p.Donut >= 1 {
+		panic("pie donut must be in the range [0, 1)")
+	}
+
+	p.headers = make([]string, len(p.indices))
+	for i, idx := range p.indices {
+		p.headers[i] = headers[idx]
+	}
+	p.values = make([]float64, len(p.indices))
+
+	p.format = (&Axis{Format: p.Format, Formatter: p.Formatter}).formatter()
+	p.scale = calcScaleCorrection()
+	p.style = p.Style.resolve()
+	p.renderer = plotRenderer{static: true}
+}
+
+// Update the drawer.
+func (p *Pie) Update(w *ecs.World) {
+	p.Observer.Update(w)
+	p.renderer.Invalidate()
+}
+
+// UpdateInputs handles input events of the previous frame update.
+// Pie charts have no axes, so there is no zoom, pan or crosshair.
+func (p *Pie) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	if savePressed(win) {
+		saveWindow(win, p.SavePath, p.scale, p.SaveAs)
+	}
+	if exportPressed(win) {
+		exportWindow(p.ExportPath, p.ExportData)
+	}
+}
+
+// Draw the drawer.
+func (p *Pie) Draw(w *ecs.World, win *opengl.Window) {
+	p.renderer.Draw(win, p.scale, p.Async, func() *figure {
+		p.updateData(w)
+		return p.buildFigure()
+	})
+}
+
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (p *Pie) SaveAs(path string, width, height vg.Length) error {
+	return p.renderer.Save(path, width, height)
+}
+
+// ExportData writes the current data of the plot to a file, with column headers from the observer.
+// The file format is selected by the extension. Supported are csv and json.
+func (p *Pie) ExportData(path string) error {
+	columns := make([]dataColumn, len(p.headers))
+	for i, name := range p.headers {
+		columns[i] = dataColumn{Name: name, Values: []float64{p.values[i]}}
+	}
+	return exportData(path, columns)
+}
+
+func (p *Pie) buildFigure() *figure {
+	plt := plot.New()
+	setLabels(plt, p.Labels, p.style)
+	plt.HideAxes()
+
+	chart := &pieChart{
+		Values:    append([]float64(nil), p.values...),
+		Colors:    make([]color.Color, len(p.values)),
+		Donut:     p.Donut,
+		LineStyle: draw.LineStyle{Color: p.style.Background, Width: p.style.LineWidth},
+		TextStyle: plt.X.Tick.Label,
+		Labels:    !p.HideLabels,
+		Percent:   !p.ValueLabels,
+		Format:    p.format,
+	}
+	for i := range chart.Colors {
+		if len(p.Colors) > 0 {
+			chart.Colors[i] = p.Colors[i%len(p.Colors)]
+		} else {
+			chart.Colors[i] = p.style.color(i)
+		}
+	}
+	plt.Add(chart)
+
+	if !p.HideLegend {
+		plt.Legend = newLegend(p.style)
+		for i, name := range p.headers {
+			plt.Legend.Add(name, barThumbnail{Color: chart.Colors[i], LineStyle: chart.LineStyle})
+		}
+	}
+
+	return &figure{plot: plt}
+}
+
+func (p *Pie) updateData(w *ecs.World) {
+	values := p.Observer.Values(w)
+	for i, idx := range p.indices {
+		p.values[i] = values[idx]
+	}
+}
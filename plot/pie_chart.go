@@ -0,0 +1,125 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Radius of pie charts, as a fraction of half the smaller side of the plot area.
+const pieRadius = 0.9
+
+// Minimum share of a slice in the total for showing its label.
+const pieMinLabelShare = 0.03
+
+// Maximum angle between points on the arcs of pie slices, in radians.
+const pieArcStep = math.Pi / 90
+
+// pieChart is a plotter for pie and donut charts.
+//
+// The chart is centered in the plot area and drawn in canvas coordinates, independent of the axes.
+// Slices start at the top and follow clockwise. Values that are not positive are left out.
+type pieChart struct {
+	Values    []float64
+	Colors    []color.Color        // Colors per value.
+	Donut     float64              // Inner radius as a fraction of the outer radius. Zero for a pie.
+	LineStyle draw.LineStyle       // Outline of slices.
+	TextStyle text.Style           // Style of slice labels. The color is chosen for contrast to the slice.
+	Labels    bool                 // Shows labels on slices.
+	Percent   bool                 // Shows percentages instead of values as labels.
+	Format    func(float64) string // Format of value labels. Optional.
+}
+
+// total returns the sum of all positive values.
+func (p *pieChart) total() float64 {
+	sum := 0.0
+	for _, v := range p.Values {
+		if v > 0 {
+			sum += v
+		}
+	}
+	return sum
+}
+
+// label returns the label of a value.
+func (p *pieChart) label(v, total float64) string {
+	if p.Percent {
+		return strconv.FormatFloat(roundSignificant(100*v/total, 3), 'g', -1, 64) + "%"
+	}
+	if p.Format != nil {
+		return p.Format(v)
+	}
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (p *pieChart) Plot(c draw.Canvas, _ *plot.Plot) {
+	total := p.total()
+	if total <= 0 {
+		return
+	}
+	center := c.Center()
+	outer := pieRadius * math.Min(float64(c.Max.X-c.Min.X), float64(c.Max.Y-c.Min.Y)) / 2
+	inner := p.Donut * outer
+	point := func(angle, radius float64) vg.Point {
+		return vg.Point{X: center.X + vg.Length(radius*math.Cos(angle)), Y: center.Y + vg.Length(radius*math.Sin(angle))}
+	}
+
+	start := 0.0
+	for i, v := range p.Values {
+		if !(v > 0) {
+			continue
+		}
+		share := v / total
+		a0 := math.Pi/2 - 2*math.Pi*start
+		a1 := math.Pi/2 - 2*math.Pi*(start+share)
+		start += share
+
+		steps := max(int(math.Ceil((a0-a1)/pieArcStep)), 1)
+		pts := make([]vg.Point, 0, 2*steps+2)
+		for s := 0; s <= steps; s++ {
+			pts = append(pts, point(a0+(a1-a0)*float64(s)/float64(steps), outer))
+		}
+		if inner > 0 {
+			for s := steps; s >= 0; s-- {
+				pts = append(pts, point(a0+(a1-a0)*float64(s)/float64(steps), inner))
+			}
+		} else {
+			pts = append(pts, center)
+		}
+		c.FillPolygon(p.Colors[i], pts)
+		c.StrokeLines(p.LineStyle, append(pts, pts[0]))
+
+		if p.Labels && share >= pieMinLabelShare {
+			sty := p.TextStyle
+			sty.Color = contrastColor(p.Colors[i])
+			sty.XAlign, sty.YAlign = draw.XCenter, draw.YCenter
+			radius := (inner + outer) / 2
+			if inner == 0 {
+				radius = 0.65 * outer
+			}
+			c.FillText(sty, point((a0+a1)/2, radius), p.label(v, total))
+		}
+	}
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+// Pie charts are independent of the axes.
+func (p *pieChart) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return -1, 1, -1, 1
+}
+
+// contrastColor returns black or white, whichever contrasts better with the given color.
+func contrastColor(c color.Color) color.Color {
+	r, g, b, _ := color.NRGBAModel.Convert(c).RGBA()
+	luminance := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 0xffff
+	if luminance > 0.5 {
+		return color.Black
+	}
+	return color.White
+}
@@ -0,0 +1,34 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPieChart(t *testing.T) {
+	chart := pieChart{
+		Values:  []float64{1, math.NaN(), 2, -1, 0, 5},
+		Percent: true,
+	}
+	total := chart.total()
+	assert.Equal(t, 8.0, total)
+	assert.Equal(t, "12.5%", chart.label(1, total))
+	assert.Equal(t, "33.3%", chart.label(8.0/3, total))
+
+	chart.Percent = false
+	assert.Equal(t, "2", chart.label(2, total))
+	assert.Equal(t, "0.3333", chart.label(1.0/3, total))
+
+	chart.Format = formatSI
+	assert.Equal(t, "1.5k", chart.label(1500, total))
+}
+
+func TestContrastColor(t *testing.T) {
+	assert.Equal(t, color.White, contrastColor(color.Black))
+	assert.Equal(t, color.Black, contrastColor(color.White))
+	assert.Equal(t, color.Black, contrastColor(color.RGBA{R: 255, G: 255, A: 255}))
+	assert.Equal(t, color.White, contrastColor(color.RGBA{B: 255, A: 255}))
+}
@@ -0,0 +1,89 @@
+package plot_test
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/ark-pixel/plot"
+	"github.com/mlange-42/ark-pixel/window"
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExamplePie() {
+
+	// Create a new model.
+	app := app.New()
+
+	// Limit the the simulation speed.
+	app.TPS = 30
+
+	// Create a donut chart.
+	// See below for the implementation of the RowObserver.
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Pie{
+			Observer: &RowObserver{},
+			Donut:    0.5, // Optional, for a donut instead of a pie.
+		}))
+
+	// Add a termination system that ends the simulation.
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+
+	app.Run()
+
+	// Run the simulation.
+	// Due to the use of the OpenGL UI system, the model must be run via [window.Run].
+	// Comment out the code line above, and uncomment the next line to run this example stand-alone.
+
+	// window.Run(app)
+
+	// Output:
+}
+
+func TestPie(t *testing.T) {
+	pie := plot.Pie{
+		Observer:    &RowObserver{},
+		Columns:     []string{"C", "A"},
+		Colors:      []color.Color{color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}},
+		ValueLabels: true,
+		Labels:      plot.Labels{Title: "Pie"},
+	}
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&pie))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "pie.csv")
+	assert.Nil(t, pie.ExportData(path))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Equal(t, "C,A", lines[0])
+}
+
+func TestPie_Panic(t *testing.T) {
+	for _, pie := range []plot.Pie{
+		{Observer: &RowObserver{}, Columns: []string{"F"}},
+		{Observer: &RowObserver{}, Donut: 1},
+	} {
+		app := app.New()
+		app.TPS = 300
+		app.AddUISystem((&window.Window{}).With(&pie))
+
+		app.AddSystem(&system.FixedTermination{
+			Steps: 100,
+		})
+		assert.Panics(t, app.Run)
+	}
+}
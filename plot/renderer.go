@@ -0,0 +1,192 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+
+	pixel "github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
+)
+
+// figure is a gonum plot prepared for rendering, with optional decorations.
+//
+// A figure must not reference any data that is modified after its creation,
+// as it may be rendered in a background goroutine.
+type figure struct {
+	plot      *plot.Plot
+	colorBar  *colorBar
+	secondary *secondaryAxis // Secondary Y axis. Optional.
+	series    []namedSeries  // Data series for the crosshair. Optional.
+}
+
+// colorBar decoration for figures.
+type colorBar struct {
+	Colors []color.Color
+	Min    float64
+	Max    float64
+}
+
+// Draw the figure to a canvas.
+func (f *figure) Draw(c draw.Canvas) {
+	c.SetColor(f.background())
+	c.Fill(c.Rectangle.Path())
+	if f.colorBar != nil {
+		c = drawColorBar(c, f.plot, f.colorBar.Colors, f.colorBar.Min, f.colorBar.Max)
+	}
+	if f.secondary != nil {
+		c = f.secondary.draw(c, f.plot)
+	}
+	f.plot.Draw(c)
+}
+
+// plotCanvas returns the part of the canvas the plot is drawn to, without decorations.
+func (f *figure) plotCanvas(c draw.Canvas) draw.Canvas {
+	if f.colorBar != nil {
+		c = draw.Crop(c, 0, -colorBarWidth, 0, 0)
+	}
+	if f.secondary != nil {
+		c = draw.Crop(c, 0, -f.secondary.width(f.plot), 0, 0)
+	}
+	return c
+}
+
+// background color of the figure, as given by the plot style.
+func (f *figure) background() color.Color {
+	if f == nil || f.plot.BackgroundColor == nil {
+		return color.White
+	}
+	return f.plot.BackgroundColor
+}
+
+// Offset of the plot from the window's lower left corner, in pixels.
+const spriteOffset = 5
+
+// plotRenderer rasterizes gonum plots for drawing them to a window.
+//
+// Re-uses the canvas and the picture between frames.
+// Only re-renders when invalidated (i.e. when data changed) or when the window was resized.
+// Otherwise, the last sprite is drawn again.
+//
+// In async mode, figures are rasterized in a background goroutine, and the finished picture
+// is swapped in on a later frame. Meanwhile, the last picture is drawn.
+type plotRenderer struct {
+	canvas  *vgimg.Canvas
+	picture *pixel.PictureData
+	spare   *pixel.PictureData
+	sprite  *pixel.Sprite
+	figure  *figure
+	width   float64
+	height  float64
+	dirty   bool
+	busy    bool
+	results chan *pixel.PictureData
+	view    plotView
+	static  bool // Disables zoom, pan and crosshair, for figures without axes.
+}
+
+// Invalidate marks the plot for re-rendering on the next draw.
+func (r *plotRenderer) Invalidate() {
+	r.dirty = true
+}
+
+// HandleInputs processes user input for zoom, pan and crosshair.
+// Does nothing for static renderers.
+func (r *plotRenderer) HandleInputs(win *opengl.Window, scale float64) {
+	if r.static {
+		return
+	}
+	if r.view.HandleInputs(win, scale) {
+		r.dirty = true
+	}
+}
+
+// Draw the plot to the window.
+// The build function is only called if the plot is out of date.
+// It is called on the main thread and must return a figure that holds a snapshot of the data.
+func (r *plotRenderer) Draw(win *opengl.Window, scale float64, async bool, build func() *figure) {
+	width := win.Canvas().Bounds().W()
+	height := win.Canvas().Bounds().H()
+	resized := width != r.width || height != r.height
+
+	if r.busy {
+		select {
+		case pic := <-r.results:
+			r.swap(pic)
+			r.busy = false
+		default:
+		}
+	}
+
+	if !r.busy && (r.sprite == nil || r.dirty || resized) {
+		if r.canvas == nil || resized {
+			r.canvas = vgimg.New(vg.Points(width*scale)-10, vg.Points(height*scale)-10)
+		}
+		r.figure = build()
+		if !r.static {
+			r.view.apply(r.figure, draw.New(r.canvas))
+		}
+		r.width, r.height = width, height
+		r.dirty = false
+
+		if async {
+			if r.results == nil {
+				r.results = make(chan *pixel.PictureData, 1)
+			}
+			r.busy = true
+			go func(canvas *vgimg.Canvas, fig *figure, spare *pixel.PictureData) {
+				r.results <- rasterize(canvas, fig, spare)
+			}(r.canvas, r.figure, r.spare)
+		} else {
+			r.swap(rasterize(r.canvas, r.figure, r.spare))
+		}
+	}
+
+	win.Clear(r.figure.background())
+	if r.sprite != nil {
+		r.sprite.Draw(win, pixel.IM.Moved(pixel.V(r.picture.Rect.W()/2.0+spriteOffset, r.picture.Rect.H()/2.0+spriteOffset)))
+		r.view.Draw(win, scale)
+	}
+}
+
+// swap in a newly rendered picture.
+func (r *plotRenderer) swap(pic *pixel.PictureData) {
+	r.spare = r.picture
+	r.picture = pic
+	// Sprites cache their pictures' textures, so we need a new one.
+	r.sprite = pixel.NewSprite(r.picture, r.picture.Bounds())
+}
+
+// rasterize a figure to the canvas, and convert it to picture data.
+// Re-uses the given picture if possible.
+func rasterize(canvas *vgimg.Canvas, fig *figure, picture *pixel.PictureData) *pixel.PictureData {
+	fig.Draw(draw.New(canvas))
+
+	return toPicture(canvas.Image(), picture)
+}
+
+// toPicture converts an image to picture data, re-using the given picture if it has the right size.
+func toPicture(img image.Image, picture *pixel.PictureData) *pixel.PictureData {
+	rgba, ok := img.(*image.RGBA)
+	if !ok {
+		return pixel.PictureDataFromImage(img)
+	}
+	bounds := rgba.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if picture == nil || picture.Stride != w || len(picture.Pix) != w*h {
+		picture = pixel.MakePictureData(pixel.R(0, 0, float64(w), float64(h)))
+	}
+
+	// Pictures are vertically flipped compared to images.
+	for y := range h {
+		src := rgba.Pix[y*rgba.Stride : y*rgba.Stride+4*w]
+		dst := picture.Pix[(h-y-1)*w : (h-y)*w]
+		for x := range dst {
+			dst[x] = color.RGBA{R: src[4*x], G: src[4*x+1], B: src[4*x+2], A: src[4*x+3]}
+		}
+	}
+	return picture
+}
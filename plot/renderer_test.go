@@ -0,0 +1,47 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/vgimg"
+)
+
+func TestToPicture(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(2, 1, color.RGBA{0, 0, 255, 255})
+
+	pic := toPicture(img, nil)
+	assert.Equal(t, 3, pic.Stride)
+	assert.Equal(t, 6, len(pic.Pix))
+	// Rows are flipped vertically.
+	assert.Equal(t, color.RGBA{255, 0, 0, 255}, pic.Pix[3])
+	assert.Equal(t, color.RGBA{0, 0, 255, 255}, pic.Pix[2])
+
+	pic2 := toPicture(img, pic)
+	assert.Same(t, pic, pic2)
+
+	pic3 := toPicture(image.NewRGBA(image.Rect(0, 0, 2, 2)), pic)
+	assert.NotSame(t, pic, pic3)
+}
+
+func TestRasterize(t *testing.T) {
+	canvas := vgimg.New(vg.Points(120), vg.Points(80))
+	fig := figure{
+		plot:     plot.New(),
+		colorBar: &colorBar{Colors: defaultPalette().Colors(), Min: 0, Max: 1},
+	}
+
+	pic := rasterize(canvas, &fig, nil)
+	bounds := canvas.Image().Bounds()
+	assert.Equal(t, bounds.Dx(), pic.Stride)
+	assert.Equal(t, bounds.Dx()*bounds.Dy(), len(pic.Pix))
+
+	pic2 := rasterize(canvas, &fig, pic)
+	assert.Same(t, pic, pic2)
+}
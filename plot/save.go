@@ -0,0 +1,75 @@
+package plot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	px "github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+
+	// Register vector formats for saving plots.
+	_ "gonum.org/v1/plot/vg/vgeps"
+	_ "gonum.org/v1/plot/vg/vgpdf"
+	_ "gonum.org/v1/plot/vg/vgsvg"
+)
+
+// Save the last rendered figure to a file.
+// Waits for pending background rendering to finish.
+func (r *plotRenderer) Save(path string, w, h vg.Length) error {
+	if r.busy {
+		r.swap(<-r.results)
+		r.busy = false
+	}
+	if r.figure == nil {
+		return errors.New("plot was not rendered yet")
+	}
+	return saveFigure(r.figure, path, w, h)
+}
+
+// saveFigure saves a figure to a file.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func saveFigure(fig *figure, path string, w, h vg.Length) error {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	c, err := draw.NewFormattedCanvas(w, h, format)
+	if err != nil {
+		return err
+	}
+	fig.Draw(draw.New(c))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err = c.WriteTo(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// savePressed checks whether the hotkey for saving plots (Ctrl+S) was pressed.
+func savePressed(win *opengl.Window) bool {
+	return win.JustPressed(px.KeyS) && (win.Pressed(px.KeyLeftControl) || win.Pressed(px.KeyRightControl))
+}
+
+// saveWindow saves a plot in the size of the window, using the given save function.
+// Uses a time-stamped PNG file name if path is empty.
+// Errors are logged rather than returned, as this is called from UI input handling.
+func saveWindow(win *opengl.Window, path string, scale float64, save func(path string, w, h vg.Length) error) {
+	if path == "" {
+		path = fmt.Sprintf("plot-%s.png", time.Now().Format("20060102-150405"))
+	}
+	bounds := win.Canvas().Bounds()
+	if err := save(path, vg.Points(bounds.W()*scale), vg.Points(bounds.H()*scale)); err != nil {
+		log.Printf("ERROR: failed to save plot to %s: %s", path, err)
+		return
+	}
+	log.Printf("Plot saved to %s", path)
+}
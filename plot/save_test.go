@@ -0,0 +1,29 @@
+package plot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestSaveFigure(t *testing.T) {
+	dir := t.TempDir()
+	fig := figure{
+		plot:     plot.New(),
+		colorBar: &colorBar{Colors: defaultPalette().Colors(), Min: 0, Max: 1},
+	}
+
+	for _, file := range []string{"plot.svg", "plot.pdf", "plot.eps", "plot.png", "plot.JPG"} {
+		path := filepath.Join(dir, file)
+		assert.Nil(t, saveFigure(&fig, path, 4*vg.Inch, 3*vg.Inch))
+		info, err := os.Stat(path)
+		assert.Nil(t, err)
+		assert.Greater(t, info.Size(), int64(0))
+	}
+
+	assert.NotNil(t, saveFigure(&fig, filepath.Join(dir, "plot.xyz"), 4*vg.Inch, 3*vg.Inch))
+}
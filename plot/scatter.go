@@ -2,37 +2,69 @@ package plot
 
 import (
 	"fmt"
-	"image/color"
+	"math"
+	"slices"
 
-	pixel "github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/mlange-42/ark-tools/observer"
 	"github.com/mlange-42/ark/ecs"
 	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
 	"gonum.org/v1/plot/vg/draw"
-	"gonum.org/v1/plot/vg/vgimg"
 )
 
 // Scatter plot drawer.
 //
 // Creates a scatter plot from multiple observers.
 // Supports multiple series per observer. The series in a particular observer must share a common X column.
+//
+// Optionally, point colors and sizes can be mapped from further columns of the observers,
+// shown by a color bar and by legend entries for sizes.
+// Sizes are mapped so that the marker area is proportional to the value.
+// Points with NaN color or size values use the series color or the style's marker size, respectively.
+//
+// For large numbers of points, the plot can be drawn as a 2D histogram with square or hexagonal bins instead,
+// showing the point counts of all series combined through the palette. See [Density].
+// Color and size mapping are not used in this mode.
 type Scatter struct {
-	Observers []observer.Table // Observers providing XY data series.
-	X         []string         // X column name per observer. Optional. Defaults to first column. Empty strings also falls back to the default.
-	Y         [][]string       // Y column names per observer. Optional. Defaults to second column. Empty strings also falls back to the default.
-	XLim      [2]float64       // X axis limits. Optional, default auto.
-	YLim      [2]float64       // Y axis limits. Optional, default auto.
-	Labels    Labels           // Labels for plot and axes. Optional.
-
-	xIndices []int
-	yIndices [][]int
-	labels   [][]string
-
-	series [][]plotter.XYs
-	scale  float64
+	Observers    []observer.Table   // Observers providing XY data series.
+	X            []string           // X column name per observer. Optional. Defaults to first column. Empty strings also falls back to the default.
+	Y            [][]string         // Y column names per observer. Optional. Defaults to second column. Empty strings also falls back to the default.
+	Color        []string           // Column name per observer for mapping point colors. Optional. Empty strings use the series color.
+	Size         []string           // Column name per observer for mapping point sizes. Optional. Empty strings use the style's marker size.
+	Palette      palette.Palette    // Color palette for color mapping. Optional, default "viridis" (see [NamedPalette]).
+	ColorLim     [2]float64         // Value range for color mapping, or count range for density. Optional, default data range.
+	SizeLim      [2]float64         // Value range for size mapping. Optional, default data range.
+	MarkerSizes  [2]vg.Length       // Range of marker radii for size mapping. Optional, default 1.5pt to 8pt.
+	Markers      []draw.GlyphDrawer // Marker shapes per series, cycled. Optional, default the style's markers.
+	HideColorBar bool               // Hides the color bar of color mapping or density.
+	Density      Density            // Draws a 2D histogram of point counts instead of points. Optional, default NoDensity.
+	DensityBins  int                // Number of density bins along the X axis. Optional, default 50.
+	XLim         [2]float64         // X axis limits. Optional, default auto.
+	YLim         [2]float64         // Y axis limits. Optional, default auto.
+	Labels       Labels             // Labels for plot and axes. Optional.
+	Style        *Style             // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	XAxis        Axis               // X axis configuration (scale, tick format, grid, ...). Optional.
+	YAxis        Axis               // Y axis configuration (scale, tick format, grid, ...). Optional.
+	Async        bool               // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath     string             // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+	ExportPath   string             // File for exporting the data with Ctrl+E, format by extension. Optional, default time-stamped CSV.
+
+	xIndices     []int
+	yIndices     [][]int
+	colorIndices []int
+	sizeIndices  []int
+	labels       [][]string
+
+	series   [][]plotter.XYs
+	colors   [][]float64
+	sizes    [][]float64
+	density  densityGrid
+	scale    float64
+	style    *Style
+	renderer plotRenderer
 }
 
 // Initialize the drawer.
@@ -44,11 +76,27 @@ func (s *Scatter) Initialize(w *ecs.World, _ *opengl.Window) {
 	if len(s.Y) != 0 && len(s.Y) != numObs {
 		panic("length of Y not equal to length of Observers")
 	}
+	if len(s.Color) != 0 && len(s.Color) != numObs {
+		panic("length of Color not equal to length of Observers")
+	}
+	if len(s.Size) != 0 && len(s.Size) != numObs {
+		panic("length of Size not equal to length of Observers")
+	}
+	if s.Palette == nil {
+		s.Palette = defaultPalette()
+	}
+	if s.MarkerSizes[0] == 0 && s.MarkerSizes[1] == 0 {
+		s.MarkerSizes = defaultMarkerSizes
+	}
 
 	s.xIndices = make([]int, numObs)
 	s.yIndices = make([][]int, numObs)
 	s.labels = make([][]string, numObs)
+	s.colorIndices = make([]int, numObs)
+	s.sizeIndices = make([]int, numObs)
 	s.series = make([][]plotter.XYs, numObs)
+	s.colors = make([][]float64, numObs)
+	s.sizes = make([][]float64, numObs)
 	var ok bool
 	for i := range numObs {
 		obs := s.Observers[i]
@@ -81,9 +129,25 @@ func (s *Scatter) Initialize(w *ecs.World, _ *opengl.Window) {
 			}
 
 		}
+		s.colorIndices[i] = -1
+		if len(s.Color) != 0 && s.Color[i] != "" {
+			s.colorIndices[i], ok = find(header, s.Color[i])
+			if !ok {
+				panic(fmt.Sprintf("color column '%s' not found", s.Color[i]))
+			}
+		}
+		s.sizeIndices[i] = -1
+		if len(s.Size) != 0 && s.Size[i] != "" {
+			s.sizeIndices[i], ok = find(header, s.Size[i])
+			if !ok {
+				panic(fmt.Sprintf("size column '%s' not found", s.Size[i]))
+			}
+		}
 	}
 
 	s.scale = calcScaleCorrection()
+	s.style = s.Style.resolve()
+	s.renderer = plotRenderer{}
 }
 
 // Update the drawer.
@@ -91,24 +155,76 @@ func (s *Scatter) Update(w *ecs.World) {
 	for _, obs := range s.Observers {
 		obs.Update(w)
 	}
+	s.renderer.Invalidate()
 }
 
 // UpdateInputs handles input events of the previous frame update.
-func (s *Scatter) UpdateInputs(_ *ecs.World, _ *opengl.Window) {}
+func (s *Scatter) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	s.renderer.HandleInputs(win, s.scale)
+	if savePressed(win) {
+		saveWindow(win, s.SavePath, s.scale, s.SaveAs)
+	}
+	if exportPressed(win) {
+		exportWindow(s.ExportPath, s.ExportData)
+	}
+}
 
 // Draw the drawer.
 func (s *Scatter) Draw(w *ecs.World, win *opengl.Window) {
-	width := win.Canvas().Bounds().W()
-	height := win.Canvas().Bounds().H()
+	s.renderer.Draw(win, s.scale, s.Async, func() *figure {
+		s.updateData(w)
+		return s.buildFigure()
+	})
+}
 
-	s.updateData(w)
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (s *Scatter) SaveAs(path string, width, height vg.Length) error {
+	return s.renderer.Save(path, width, height)
+}
 
-	c := vgimg.New(vg.Points(width*s.scale)-10, vg.Points(height*s.scale)-10)
+// ExportData writes the current data of the plot to a file, with column headers from the observer.
+// Columns for color and size mapping are exported after the Y columns of their observer.
+// In density mode, exports the centers and counts of all non-empty bins instead.
+// The file format is selected by the extension. Supported are csv and json.
+func (s *Scatter) ExportData(path string) error {
+	if s.Density != NoDensity {
+		return exportData(path, s.densityColumns())
+	}
+	columns := []dataColumn{}
+	for i, xi := range s.xIndices {
+		header := s.Observers[i].Header()
+		x := dataColumn{Name: header[xi]}
+		if len(s.series[i]) > 0 {
+			x.Values = make([]float64, len(s.series[i][0]))
+			for k, pt := range s.series[i][0] {
+				x.Values[k] = pt.X
+			}
+		}
+		columns = append(columns, x)
+		for j, series := range s.series[i] {
+			col := dataColumn{Name: s.labels[i][j], Values: make([]float64, len(series))}
+			for k, pt := range series {
+				col.Values[k] = pt.Y
+			}
+			columns = append(columns, col)
+		}
+		if ci := s.colorIndices[i]; ci >= 0 {
+			columns = append(columns, dataColumn{Name: header[ci], Values: s.colors[i]})
+		}
+		if si := s.sizeIndices[i]; si >= 0 && si != s.colorIndices[i] {
+			columns = append(columns, dataColumn{Name: header[si], Values: s.sizes[i]})
+		}
+	}
+	return exportData(path, columns)
+}
 
+func (s *Scatter) buildFigure() *figure {
 	p := plot.New()
-	setLabels(p, s.Labels)
+	setLabels(p, s.Labels, s.style)
 
 	p.X.Tick.Marker = removeLastTicks{}
+	setAxes(p, s.XAxis, s.YAxis, s.style)
 
 	if s.XLim[0] != 0 || s.XLim[1] != 0 {
 		p.X.Min = s.XLim[0]
@@ -119,36 +235,96 @@ func (s *Scatter) Draw(w *ecs.World, win *opengl.Window) {
 		p.Y.Max = s.YLim[1]
 	}
 
-	p.Legend = plot.NewLegend()
-	p.Legend.TextStyle.Font.Variant = "Mono"
+	fig := figure{plot: p}
+
+	p.Legend = newLegend(s.style)
+
+	cols := s.Palette.Colors()
+	if s.Density != NoDensity {
+		grid := s.density
+		grid.Counts = slices.Clone(grid.Counts)
+		counts := newValueMapping([][]float64{{0, grid.maxCount()}}, s.ColorLim)
+		p.Add(&densityPlotter{Grid: grid, Colors: cols, Min: counts.Min, Max: counts.Max})
+		if !s.HideColorBar {
+			fig.colorBar = &colorBar{Colors: cols, Min: counts.Min, Max: counts.Max}
+		}
+		return &fig
+	}
+
+	colorMap := newValueMapping(s.colors, s.ColorLim)
+	sizeMap := newValueMapping(s.sizes, s.SizeLim)
 
 	cnt := 0
+	sizeColumn := ""
 	for i := range s.xIndices {
+		// Copies, as the figure may be rendered while the drawer updates its data.
+		colors, sizes := slices.Clone(s.colors[i]), slices.Clone(s.sizes[i])
+		hasColors, hasSizes := s.colorIndices[i] >= 0, s.sizeIndices[i] >= 0
+		if hasSizes && sizeColumn == "" {
+			sizeColumn = s.Observers[i].Header()[s.sizeIndices[i]]
+		}
 		ys := s.yIndices[i]
 		for j := range ys {
 			points, err := plotter.NewScatter(s.series[i][j])
 			if err != nil {
 				panic(err)
 			}
-			points.Shape = draw.CircleGlyph{}
-			points.Color = defaultColors[cnt%len(defaultColors)]
+			points.GlyphStyle = s.glyphStyle(cnt)
+			if hasColors || hasSizes {
+				base := points.GlyphStyle
+				points.GlyphStyleFunc = func(k int) draw.GlyphStyle {
+					sty := base
+					if hasColors {
+						if c := colorMap.color(cols, colors[k]); c != nil {
+							sty.Color = c
+						}
+					}
+					if hasSizes {
+						if r := sizeMap.size(s.MarkerSizes, sizes[k]); r > 0 {
+							sty.Radius = r
+						}
+					}
+					return sty
+				}
+			}
 			p.Add(points)
-			p.Legend.Add(s.labels[i][j], points)
+
+			legend := points.GlyphStyle
+			if hasColors {
+				legend.Color = s.style.Foreground
+			}
+			p.Legend.Add(s.labels[i][j], glyphThumbnail{legend})
+			fig.series = append(fig.series, namedSeries{Name: s.labels[i][j], XYs: points.XYs})
 			cnt++
 		}
 	}
 
-	win.Clear(color.White)
-	p.Draw(draw.New(c))
+	if sizeColumn != "" {
+		addSizeLegend(&p.Legend, sizeColumn, sizeMap, s.MarkerSizes,
+			draw.GlyphStyle{Color: s.style.Foreground, Shape: draw.RingGlyph{}})
+	}
+	if !s.HideColorBar && slices.ContainsFunc(s.colorIndices, func(idx int) bool { return idx >= 0 }) {
+		fig.colorBar = &colorBar{Colors: cols, Min: colorMap.Min, Max: colorMap.Max}
+	}
 
-	img := c.Image()
-	picture := pixel.PictureDataFromImage(img)
+	return &fig
+}
 
-	sprite := pixel.NewSprite(picture, picture.Bounds())
-	sprite.Draw(win, pixel.IM.Moved(pixel.V(picture.Rect.W()/2.0+5, picture.Rect.H()/2.0+5)))
+// glyphStyle returns the glyph style of the series with the given index,
+// with the marker shape overridden by the drawer's markers.
+func (s *Scatter) glyphStyle(i int) draw.GlyphStyle {
+	sty := s.style.glyphStyle(i)
+	if len(s.Markers) > 0 {
+		sty.Shape = s.Markers[i%len(s.Markers)]
+	}
+	return sty
 }
 
 func (s *Scatter) updateData(w *ecs.World) {
+	if s.Density != NoDensity {
+		s.updateDensity(w)
+		return
+	}
 	xis := s.xIndices
 
 	for i := range xis {
@@ -161,5 +337,89 @@ func (s *Scatter) updateData(w *ecs.World) {
 				s.series[i][j] = append(s.series[i][j], plotter.XY{X: row[xi], Y: row[ys[j]]})
 			}
 		}
+		if ci := s.colorIndices[i]; ci >= 0 {
+			s.colors[i] = s.colors[i][:0]
+			for _, row := range data {
+				s.colors[i] = append(s.colors[i], row[ci])
+			}
+		}
+		if si := s.sizeIndices[i]; si >= 0 {
+			s.sizes[i] = s.sizes[i][:0]
+			for _, row := range data {
+				s.sizes[i] = append(s.sizes[i], row[si])
+			}
+		}
+	}
+}
+
+// updateDensity counts the points of all series in the density grid.
+// Uses the axis limits as the range of the grid, or the data range if they are not set.
+func (s *Scatter) updateDensity(w *ecs.World) {
+	xmin, xmax := s.XLim[0], s.XLim[1]
+	ymin, ymax := s.YLim[0], s.YLim[1]
+	autoX, autoY := xmin == 0 && xmax == 0, ymin == 0 && ymax == 0
+	if autoX {
+		xmin, xmax = math.Inf(1), math.Inf(-1)
+	}
+	if autoY {
+		ymin, ymax = math.Inf(1), math.Inf(-1)
+	}
+
+	tables := make([][][]float64, len(s.xIndices))
+	for i, xi := range s.xIndices {
+		tables[i] = s.Observers[i].Values(w)
+		if !autoX && !autoY {
+			continue
+		}
+		for _, row := range tables[i] {
+			for _, yi := range s.yIndices[i] {
+				x, y := row[xi], row[yi]
+				if math.IsNaN(x) || math.IsNaN(y) {
+					continue
+				}
+				if autoX {
+					xmin, xmax = math.Min(xmin, x), math.Max(xmax, x)
+				}
+				if autoY {
+					ymin, ymax = math.Min(ymin, y), math.Max(ymax, y)
+				}
+			}
+		}
+	}
+	if math.IsInf(xmin, 1) {
+		xmin, xmax = 0, 1
+	}
+	if math.IsInf(ymin, 1) {
+		ymin, ymax = 0, 1
+	}
+
+	bins := s.DensityBins
+	if bins <= 0 {
+		bins = defaultDensityBins
+	}
+	s.density.reset(s.Density == HexDensity, bins, xmin, xmax, ymin, ymax)
+	for i, xi := range s.xIndices {
+		for _, row := range tables[i] {
+			for _, yi := range s.yIndices[i] {
+				s.density.add(row[xi], row[yi])
+			}
+		}
+	}
+}
+
+// densityColumns returns the centers and counts of all non-empty density bins, for export.
+func (s *Scatter) densityColumns() []dataColumn {
+	x := dataColumn{Name: "X"}
+	y := dataColumn{Name: "Y"}
+	count := dataColumn{Name: "Count"}
+	for i, c := range s.density.Counts {
+		if c == 0 {
+			continue
+		}
+		cx, cy := s.density.center(i)
+		x.Values = append(x.Values, cx)
+		y.Values = append(y.Values, cy)
+		count.Values = append(count.Values, c)
 	}
+	return []dataColumn{x, y, count}
 }
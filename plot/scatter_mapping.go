@@ -0,0 +1,90 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Default range of marker radii for size mapping, in points.
+var defaultMarkerSizes = [2]vg.Length{vg.Points(1.5), vg.Points(8)}
+
+// Number of entries of the size legend.
+const sizeLegendEntries = 3
+
+// valueMapping maps data values from a range to a color or a marker size.
+type valueMapping struct {
+	Min, Max float64
+}
+
+// newValueMapping creates a mapping for the range of the values, ignoring NaN.
+// The range is used instead of the data range if it is not zero.
+// Expands empty ranges to avoid division by zero.
+func newValueMapping(values [][]float64, rng [2]float64) valueMapping {
+	lo, hi := rng[0], rng[1]
+	if lo == 0 && hi == 0 {
+		lo, hi = math.Inf(1), math.Inf(-1)
+		for _, vs := range values {
+			for _, v := range vs {
+				if !math.IsNaN(v) {
+					lo, hi = math.Min(lo, v), math.Max(hi, v)
+				}
+			}
+		}
+		if math.IsInf(lo, 1) {
+			lo, hi = 0, 1
+		}
+	}
+	if !(hi > lo) {
+		lo, hi = lo-0.5, hi+0.5
+	}
+	return valueMapping{Min: lo, Max: hi}
+}
+
+// norm returns the position of the value in the range, clamped to [0, 1].
+func (m valueMapping) norm(v float64) float64 {
+	return math.Min(math.Max((v-m.Min)/(m.Max-m.Min), 0), 1)
+}
+
+// color maps a value to one of the colors. Returns nil for NaN.
+func (m valueMapping) color(cols []color.Color, v float64) color.Color {
+	if math.IsNaN(v) {
+		return nil
+	}
+	idx := int(m.norm(v) * float64(len(cols)))
+	return cols[min(idx, len(cols)-1)]
+}
+
+// size maps a value to a marker radius, so that the marker area is proportional to the value.
+// Returns zero for NaN.
+func (m valueMapping) size(sizes [2]vg.Length, v float64) vg.Length {
+	if math.IsNaN(v) {
+		return 0
+	}
+	r0, r1 := float64(sizes[0]), float64(sizes[1])
+	return vg.Length(math.Sqrt(r0*r0 + m.norm(v)*(r1*r1-r0*r0)))
+}
+
+// glyphThumbnail is a legend entry showing a single glyph.
+type glyphThumbnail struct {
+	draw.GlyphStyle
+}
+
+// Thumbnail implements the Thumbnail method of the plot.Thumbnailer interface.
+func (t glyphThumbnail) Thumbnail(c *draw.Canvas) {
+	c.DrawGlyph(t.GlyphStyle, c.Center())
+}
+
+// addSizeLegend adds legend entries for marker sizes, for values evenly spaced over the range of the mapping.
+func addSizeLegend(l *plot.Legend, name string, m valueMapping, sizes [2]vg.Length, glyph draw.GlyphStyle) {
+	for i := range sizeLegendEntries {
+		v := m.Min + float64(i)*(m.Max-m.Min)/(sizeLegendEntries-1)
+		sty := glyph
+		sty.Radius = m.size(sizes, v)
+		l.Add(name+" "+strconv.FormatFloat(v, 'g', 3, 64), glyphThumbnail{sty})
+	}
+}
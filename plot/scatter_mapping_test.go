@@ -0,0 +1,46 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestValueMapping(t *testing.T) {
+	m := newValueMapping([][]float64{{1, math.NaN(), 3}, nil, {5}}, [2]float64{})
+	assert.Equal(t, valueMapping{Min: 1, Max: 5}, m)
+
+	m = newValueMapping([][]float64{{1, 3}}, [2]float64{0, 10})
+	assert.Equal(t, valueMapping{Min: 0, Max: 10}, m)
+
+	m = newValueMapping([][]float64{{2, 2}}, [2]float64{})
+	assert.Equal(t, valueMapping{Min: 1.5, Max: 2.5}, m)
+
+	m = newValueMapping([][]float64{{math.NaN()}}, [2]float64{})
+	assert.Equal(t, valueMapping{Min: 0, Max: 1}, m)
+}
+
+func TestValueMappingColor(t *testing.T) {
+	cols := []color.Color{color.Black, color.White}
+	m := valueMapping{Min: 0, Max: 10}
+
+	assert.Equal(t, color.Black, m.color(cols, -1))
+	assert.Equal(t, color.Black, m.color(cols, 4.9))
+	assert.Equal(t, color.White, m.color(cols, 5))
+	assert.Equal(t, color.White, m.color(cols, 10))
+	assert.Equal(t, color.White, m.color(cols, 20))
+	assert.Nil(t, m.color(cols, math.NaN()))
+}
+
+func TestValueMappingSize(t *testing.T) {
+	sizes := [2]vg.Length{1, 3}
+	m := valueMapping{Min: 0, Max: 1}
+
+	assert.Equal(t, vg.Length(1), m.size(sizes, -1))
+	assert.Equal(t, vg.Length(3), m.size(sizes, 2))
+	assert.InDelta(t, math.Sqrt(5), float64(m.size(sizes, 0.5)), 1e-12)
+	assert.Equal(t, vg.Length(0), m.size(sizes, math.NaN()))
+}
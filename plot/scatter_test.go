@@ -9,6 +9,8 @@ import (
 	"github.com/mlange-42/ark-tools/observer"
 	"github.com/mlange-42/ark-tools/system"
 	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
 )
 
 func ExampleScatter() {
@@ -142,3 +144,85 @@ func TestScatter_PanicY(t *testing.T) {
 	})
 	assert.Panics(t, app.Run)
 }
+
+func TestScatter_Mapping(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Scatter{
+			Observers: []observer.Table{
+				&TableObserver{},
+			},
+			X:           []string{"X"},
+			Y:           [][]string{{"A", "B"}},
+			Color:       []string{"C"},
+			Size:        []string{"C"},
+			Palette:     plot.NamedPalette("plasma", 32),
+			MarkerSizes: [2]vg.Length{vg.Points(2), vg.Points(10)},
+			Markers:     []draw.GlyphDrawer{draw.SquareGlyph{}, draw.TriangleGlyph{}},
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestScatter_PanicColor(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Scatter{
+			Observers: []observer.Table{
+				&TableObserver{},
+			},
+			Color: []string{"F"},
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	assert.Panics(t, app.Run)
+}
+
+func TestScatter_PanicSizeCount(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Scatter{
+			Observers: []observer.Table{
+				&TableObserver{},
+			},
+			Size: []string{"A", "B"},
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	assert.Panics(t, app.Run)
+}
+
+func TestScatter_Density(t *testing.T) {
+	for _, density := range []plot.Density{plot.SquareDensity, plot.HexDensity} {
+		app := app.New()
+		app.TPS = 300
+
+		app.AddUISystem((&window.Window{}).
+			With(&plot.Scatter{
+				Observers: []observer.Table{
+					&TableObserver{},
+				},
+				Y:           [][]string{{"A", "B", "C"}},
+				Density:     density,
+				DensityBins: 20,
+			}))
+
+		app.AddSystem(&system.FixedTermination{
+			Steps: 100,
+		})
+		app.Run()
+	}
+}
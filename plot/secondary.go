@@ -0,0 +1,224 @@
+package plot
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// secondaryAxis decoration for figures: a linear Y axis on the right side of the plot.
+//
+// Series of the secondary axis are transformed linearly to the range of the primary Y axis
+// before they are added to the plot. The axis reverts this transformation for its ticks,
+// so it also follows zoom and pan of the primary axis.
+type secondaryAxis struct {
+	Label  string
+	Axis   Axis    // Axis configuration. Only the tick format is used.
+	Scale  float64 // Secondary value = primary value * Scale + Offset.
+	Offset float64
+}
+
+// newSecondaryAxis creates a secondary axis that maps the value range of the secondary series
+// to the value range of the primary series.
+func newSecondaryAxis(primary, secondary []plotter.XYs, label string, axis Axis) *secondaryAxis {
+	sLo, sHi := yRange(secondary)
+	pLo, pHi := yRange(primary)
+	if math.IsInf(pLo, 0) {
+		pLo, pHi = sLo, sHi
+	}
+	if math.IsInf(sLo, 0) {
+		sLo, sHi = pLo, pHi
+	}
+	if math.IsInf(sLo, 0) {
+		sLo, sHi, pLo, pHi = 0, 1, 0, 1
+	}
+	if !(sHi > sLo) {
+		sLo, sHi = sLo-0.5, sHi+0.5
+	}
+	if !(pHi > pLo) {
+		pLo, pHi = pLo-0.5, pHi+0.5
+	}
+	scale := (sHi - sLo) / (pHi - pLo)
+	return &secondaryAxis{
+		Label:  label,
+		Axis:   axis,
+		Scale:  scale,
+		Offset: sLo - pLo*scale,
+	}
+}
+
+// transform appends the series, transformed to values of the primary axis, to dst[:0].
+func (a *secondaryAxis) transform(dst, xys plotter.XYs) plotter.XYs {
+	dst = dst[:0]
+	for _, pt := range xys {
+		dst = append(dst, plotter.XY{X: pt.X, Y: (pt.Y - a.Offset) / a.Scale})
+	}
+	return dst
+}
+
+// ticks returns the ticks of the axis, for the current range of the primary axis.
+func (a *secondaryAxis) ticks(p *plot.Plot) []plot.Tick {
+	var ticker plot.Ticker = plot.DefaultTicks{}
+	if format := a.Axis.formatter(); format != nil {
+		ticker = formattedTicks{Ticker: ticker, Format: format}
+	}
+	ticks := ticker.Ticks(p.Y.Min*a.Scale+a.Offset, p.Y.Max*a.Scale+a.Offset)
+	// Right-pads tick labels to avoid a jumping axis, like paddedTicks for the primary axis.
+	for i := range ticks {
+		if !ticks[i].IsMinor() {
+			ticks[i].Label = fmt.Sprintf("%-*s", 10, ticks[i].Label)
+		}
+	}
+	return ticks
+}
+
+// width of the axis, including ticks, tick labels and the axis label.
+func (a *secondaryAxis) width(p *plot.Plot) vg.Length {
+	w := p.Y.Padding + p.Y.Width/2 + p.Y.Tick.Length
+	labelWidth := vg.Length(0)
+	for _, t := range a.ticks(p) {
+		if !t.IsMinor() {
+			labelWidth = max(labelWidth, p.Y.Tick.Label.Width(t.Label))
+		}
+	}
+	if labelWidth > 0 {
+		w += labelWidth + p.Y.Tick.Label.Width(" ")
+	}
+	if a.Label != "" {
+		sty := p.Y.Label.TextStyle
+		w += p.Y.Label.Padding + sty.Height(a.Label) + sty.FontExtents().Descent
+	}
+	return w
+}
+
+// draw the axis into the right margin of the canvas, and return the remaining canvas for the plot.
+// The axis is aligned to the data area of plot p when drawn into the returned canvas.
+func (a *secondaryAxis) draw(c draw.Canvas, p *plot.Plot) draw.Canvas {
+	plotCanvas := draw.Crop(c, 0, -a.width(p), 0, 0)
+	dataCanvas := p.DataCanvas(plotCanvas)
+
+	x := dataCanvas.Max.X + p.Y.Padding
+	c.StrokeLine2(p.Y.LineStyle, x, dataCanvas.Min.Y, x, dataCanvas.Max.Y)
+
+	ticks := a.ticks(p)
+	labelStyle := p.Y.Tick.Label
+	labelStyle.XAlign = draw.XLeft
+	descent := labelStyle.FontExtents().Descent
+	labelX := x + p.Y.Tick.Length + labelStyle.Width(" ")
+	for _, t := range ticks {
+		y := dataCanvas.Y(p.Y.Norm((t.Value - a.Offset) / a.Scale))
+		if !dataCanvas.ContainsY(y) {
+			continue
+		}
+		length := p.Y.Tick.Length
+		if t.IsMinor() {
+			length /= 2
+		} else {
+			c.FillText(labelStyle, vg.Point{X: labelX, Y: y + descent}, t.Label)
+		}
+		c.StrokeLine2(p.Y.Tick.LineStyle, x, y, x+length, y)
+	}
+
+	if a.Label != "" {
+		sty := p.Y.Label.TextStyle
+		sty.Rotation += math.Pi / 2
+		lx := c.Max.X - sty.FontExtents().Descent
+		c.FillText(sty, vg.Point{X: lx, Y: dataCanvas.Center().Y}, a.Label)
+	}
+
+	return plotCanvas
+}
+
+// yRange calculates the range of Y values of all series, ignoring NaN.
+// Returns infinite values if there is no data.
+func yRange(series []plotter.XYs) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, pt := range s {
+			if !math.IsNaN(pt.Y) {
+				lo, hi = math.Min(lo, pt.Y), math.Max(hi, pt.Y)
+			}
+		}
+	}
+	return lo, hi
+}
+
+// secondaryFlags determines for each shown column whether it is plotted against the secondary axis.
+// Indices are the shown columns, and names are the columns of the secondary axis.
+// Panics if a secondary column is not among the shown columns.
+func secondaryFlags(headers []string, indices []int, names []string) []bool {
+	flags := make([]bool, len(indices))
+	for _, name := range names {
+		idx, ok := find(headers, name)
+		if ok {
+			idx, ok = find(indices, idx)
+		}
+		if !ok {
+			panic(fmt.Sprintf("y2 column '%s' not found", name))
+		}
+		flags[idx] = true
+	}
+	return flags
+}
+
+// secondaryFor creates a secondary axis for the series flagged as secondary.
+// Returns nil if no series is flagged.
+func secondaryFor(series []plotter.XYs, flags []bool, label string, axis Axis) *secondaryAxis {
+	var primary, secondary []plotter.XYs
+	for i, s := range series {
+		if flags[i] {
+			secondary = append(secondary, s)
+		} else {
+			primary = append(primary, s)
+		}
+	}
+	if len(secondary) == 0 {
+		return nil
+	}
+	return newSecondaryAxis(primary, secondary, label, axis)
+}
+
+// secondaryName marks the legend name of a series on the secondary axis.
+func secondaryName(name string) string {
+	return name + " (right)"
+}
+
+// addLine adds a line series with a legend entry to the figure's plot.
+// Series on the secondary axis are transformed to the primary axis.
+// The optional band is shown in the legend entry of the line.
+// Returns the series for the crosshair.
+func addLine(fig *figure, xys plotter.XYs, name string, secondary bool, style draw.LineStyle, band *bandPlotter) namedSeries {
+	var values plotter.XYs
+	if secondary {
+		values = append(plotter.XYs(nil), xys...)
+		xys = fig.secondary.transform(nil, xys)
+		name = secondaryName(name)
+	}
+	lines, err := plotter.NewLine(xys)
+	if err != nil {
+		panic(err)
+	}
+	lines.LineStyle = style
+	fig.plot.Add(lines)
+	if band != nil {
+		fig.plot.Legend.Add(name, band, lines)
+	} else {
+		fig.plot.Legend.Add(name, lines)
+	}
+	return namedSeries{Name: name, XYs: lines.XYs, Values: values}
+}
+
+// addBand adds a band to the figure's plot.
+// Bands on the secondary axis are transformed to the primary axis.
+// Bands should be added before lines, so that they don't cover them.
+func addBand(fig *figure, band *bandPlotter, secondary bool) {
+	if secondary {
+		band.Lower = fig.secondary.transform(band.Lower, band.Lower)
+		band.Upper = fig.secondary.transform(band.Upper, band.Upper)
+	}
+	fig.plot.Add(band)
+}
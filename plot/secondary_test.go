@@ -0,0 +1,69 @@
+package plot
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestSecondaryAxis(t *testing.T) {
+	primary := []plotter.XYs{{{X: 0, Y: 100}, {X: 1, Y: 200}}}
+	secondary := []plotter.XYs{{{X: 0, Y: 0.5}, {X: 1, Y: 1.5}}}
+
+	a := newSecondaryAxis(primary, secondary, "Y2", Axis{})
+	assert.InDelta(t, 0.01, a.Scale, 1e-12)
+	assert.InDelta(t, -0.5, a.Offset, 1e-12)
+
+	xys := a.transform(nil, secondary[0])
+	assert.InDelta(t, 100, xys[0].Y, 1e-9)
+	assert.InDelta(t, 200, xys[1].Y, 1e-9)
+	assert.Equal(t, 1.0, xys[1].X)
+
+	a = newSecondaryAxis(nil, secondary, "", Axis{})
+	assert.InDelta(t, 1, a.Scale, 1e-12)
+	assert.InDelta(t, 0, a.Offset, 1e-12)
+
+	a = newSecondaryAxis(primary, []plotter.XYs{{}}, "", Axis{})
+	assert.InDelta(t, 1, a.Scale, 1e-12)
+}
+
+func TestSecondaryFlags(t *testing.T) {
+	headers := []string{"A", "B", "C"}
+	assert.Equal(t, []bool{false, true}, secondaryFlags(headers, []int{0, 2}, []string{"C"}))
+	assert.Equal(t, []bool{false, false}, secondaryFlags(headers, []int{0, 2}, nil))
+	assert.Panics(t, func() { secondaryFlags(headers, []int{0, 2}, []string{"B"}) })
+	assert.Panics(t, func() { secondaryFlags(headers, []int{0, 2}, []string{"D"}) })
+}
+
+func TestSecondaryFigure(t *testing.T) {
+	series := []plotter.XYs{
+		{{X: 0, Y: 100}, {X: 1, Y: 200}},
+		{{X: 0, Y: 0.5}, {X: 1, Y: 1.5}},
+	}
+	p := plot.New()
+	setLabels(p, Labels{Y: "Y", Y2: "Y2"}, LightStyle())
+	fig := figure{plot: p}
+	fig.secondary = secondaryFor(series, []bool{false, true}, "Y2", Axis{Format: PercentFormat})
+	assert.NotNil(t, fig.secondary)
+	assert.Nil(t, secondaryFor(series, []bool{false, false}, "", Axis{}))
+
+	p.Legend = newLegend(LightStyle())
+	s := addLine(&fig, series[1], "B", true, LightStyle().lineStyle(1), nil)
+	assert.Equal(t, "B (right)", s.Name)
+	assert.Equal(t, series[1], s.Values)
+	assert.InDelta(t, 200, s.XYs[1].Y, 1e-9)
+
+	labels := map[float64]string{}
+	for _, tick := range fig.secondary.ticks(p) {
+		labels[tick.Value] = tick.Label
+	}
+	assert.Equal(t, "100%      ", labels[1])
+	assert.Greater(t, fig.secondary.width(p), vg.Length(0))
+
+	path := filepath.Join(t.TempDir(), "plot.svg")
+	assert.Nil(t, saveFigure(&fig, path, 4*vg.Inch, 3*vg.Inch))
+}
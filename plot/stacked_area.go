@@ -0,0 +1,233 @@
+package plot
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark/ecs"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Default fill opacity of stacked areas.
+const defaultAreaOpacity = 0.85
+
+// StackedArea plot drawer.
+//
+// Creates a stacked area per column of the observer, with the first column at the bottom.
+// Adds one row to the data per update, with the drawer's update steps as X values.
+// Particularly useful for compositions, like age classes or species shares.
+//
+// Values are expected to be non-negative. NaN values are treated as zero.
+// With Normalize, the areas show the share of each column in the total of each row.
+type StackedArea struct {
+	Observer       observer.Row // Observer providing a data row per update.
+	Columns        []string     // Columns to show, by name. Optional, default all.
+	Normalize      bool         // Stacks shares of the row total, up to 100%, instead of absolute values. Optional.
+	Opacity        float64      // Fill opacity of the areas. Optional, default 0.85.
+	UpdateInterval int          // Interval for getting data from the the observer, in model ticks. Optional.
+	MaxRows        int          // Maximum number of rows to keep. Zero means unlimited. Optional.
+	Labels         Labels       // Labels for plot and axes. Optional.
+	Style          *Style       // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	XAxis          Axis         // X axis configuration (scale, tick format, grid, ...). Optional.
+	YAxis          Axis         // Y axis configuration (scale, tick format, grid, ...). Optional. Use PercentFormat with Normalize.
+	Async          bool         // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath       string       // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+	ExportPath     string       // File for exporting the data with Ctrl+E, format by extension. Optional, default time-stamped CSV.
+
+	indices  []int
+	headers  []string
+	series   []plotter.XYs
+	scale    float64
+	style    *Style
+	step     int64
+	renderer plotRenderer
+}
+
+// Initialize the drawer.
+func (s *StackedArea) Initialize(w *ecs.World, _ *opengl.Window) {
+	s.Observer.Initialize(w)
+
+	headers := s.Observer.Header()
+
+	if len(s.Columns) == 0 {
+		s.indices = make([]int, len(headers))
+		for i := range s.indices {
+			s.indices[i] = i
+		}
+	} else {
+		s.indices = make([]int, len(s.Columns))
+		var ok bool
+		for i, col := range s.Columns {
+			s.indices[i], ok = find(headers, col)
+			if !ok {
+				panic(fmt.Sprintf("column '%s' not found", col))
+			}
+		}
+	}
+
+	s.headers = make([]string, len(s.indices))
+	for i, idx := range s.indices {
+		s.headers[i] = headers[idx]
+	}
+	s.series = make([]plotter.XYs, len(s.indices))
+
+	s.scale = calcScaleCorrection()
+	s.style = s.Style.resolve()
+	s.step = 0
+	s.renderer = plotRenderer{}
+}
+
+// Update the drawer.
+func (s *StackedArea) Update(w *ecs.World) {
+	s.Observer.Update(w)
+	if s.UpdateInterval <= 1 || s.step%int64(s.UpdateInterval) == 0 {
+		values := s.Observer.Values(w)
+		for i, idx := range s.indices {
+			s.series[i] = append(s.series[i], plotter.XY{X: float64(s.step), Y: values[idx]})
+			if s.MaxRows > 0 && len(s.series[i]) > s.MaxRows {
+				s.series[i] = s.series[i][len(s.series[i])-s.MaxRows:]
+			}
+		}
+		s.renderer.Invalidate()
+	}
+	s.step++
+}
+
+// UpdateInputs handles input events of the previous frame update.
+func (s *StackedArea) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	s.renderer.HandleInputs(win, s.scale)
+	if savePressed(win) {
+		saveWindow(win, s.SavePath, s.scale, s.SaveAs)
+	}
+	if exportPressed(win) {
+		exportWindow(s.ExportPath, s.ExportData)
+	}
+}
+
+// Draw the drawer.
+func (s *StackedArea) Draw(_ *ecs.World, win *opengl.Window) {
+	s.renderer.Draw(win, s.scale, s.Async, s.buildFigure)
+}
+
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (s *StackedArea) SaveAs(path string, width, height vg.Length) error {
+	return s.renderer.Save(path, width, height)
+}
+
+// ExportData writes the current data of the plot to a file, with column headers from the observer.
+// Values are exported as observed, i.e. neither stacked nor normalized.
+// The file format is selected by the extension. Supported are csv and json.
+func (s *StackedArea) ExportData(path string) error {
+	columns := make([]dataColumn, 0, len(s.indices)+1)
+	x := dataColumn{Name: "Tick"}
+	if len(s.series) > 0 {
+		x.Values = make([]float64, len(s.series[0]))
+		for i, pt := range s.series[0] {
+			x.Values[i] = pt.X
+		}
+	}
+	columns = append(columns, x)
+	for i, name := range s.headers {
+		col := dataColumn{Name: name, Values: make([]float64, len(s.series[i]))}
+		for j, pt := range s.series[i] {
+			col.Values[j] = pt.Y
+		}
+		columns = append(columns, col)
+	}
+	return exportData(path, columns)
+}
+
+func (s *StackedArea) buildFigure() *figure {
+	p := plot.New()
+	setLabels(p, s.Labels, s.style)
+
+	p.X.Tick.Marker = removeLastTicks{}
+	setAxes(p, s.XAxis, s.YAxis, s.style)
+
+	if s.Normalize {
+		p.Y.Min = 0
+		p.Y.Max = 1
+	}
+
+	p.Legend = newLegend(s.style)
+
+	opacity := s.Opacity
+	if opacity <= 0 {
+		opacity = defaultAreaOpacity
+	}
+
+	fig := figure{plot: p}
+	areas := stackSeries(s.series, s.Normalize)
+	for i := range areas {
+		area := areas[i]
+		area.Color = bandColor(s.style.color(i), opacity)
+		p.Add(area)
+
+		lines, err := plotter.NewLine(area.Upper)
+		if err != nil {
+			panic(err)
+		}
+		lines.LineStyle = s.style.lineStyle(i)
+		lines.LineStyle.Dashes = nil
+		p.Add(lines)
+
+		fig.series = append(fig.series, namedSeries{Name: s.headers[i], XYs: area.Upper, Values: s.series[i]})
+	}
+	// Legend from top to bottom, like the stacked areas.
+	for i := len(areas) - 1; i >= 0; i-- {
+		p.Legend.Add(s.headers[i], areas[i])
+	}
+
+	return &fig
+}
+
+// stackSeries stacks aligned series, i.e. with shared X values, into areas.
+// NaN values are treated as zero.
+// If normalize is true, values are divided by the total of their row.
+func stackSeries(series []plotter.XYs, normalize bool) []*bandPlotter {
+	areas := make([]*bandPlotter, len(series))
+	if len(series) == 0 {
+		return areas
+	}
+	rows := len(series[0])
+	totals := make([]float64, rows)
+	if normalize {
+		for _, s := range series {
+			for r, pt := range s {
+				if !math.IsNaN(pt.Y) {
+					totals[r] += pt.Y
+				}
+			}
+		}
+	}
+
+	lower := make(plotter.XYs, rows)
+	for r := range lower {
+		lower[r] = plotter.XY{X: series[0][r].X}
+	}
+	for i, s := range series {
+		upper := make(plotter.XYs, rows)
+		for r, pt := range s {
+			y := pt.Y
+			if math.IsNaN(y) {
+				y = 0
+			}
+			if normalize {
+				if totals[r] > 0 {
+					y /= totals[r]
+				} else {
+					y = 0
+				}
+			}
+			upper[r] = plotter.XY{X: pt.X, Y: lower[r].Y + y}
+		}
+		areas[i] = &bandPlotter{Lower: lower, Upper: upper}
+		lower = upper
+	}
+	return areas
+}
@@ -0,0 +1,28 @@
+package plot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestStackSeries(t *testing.T) {
+	series := []plotter.XYs{
+		{{X: 0, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 0}},
+		{{X: 0, Y: 3}, {X: 1, Y: math.NaN()}, {X: 2, Y: 0}},
+	}
+	areas := stackSeries(series, false)
+	assert.Equal(t, 2, len(areas))
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}, areas[0].Lower)
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 0}}, areas[0].Upper)
+	assert.Equal(t, areas[0].Upper, areas[1].Lower)
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 4}, {X: 1, Y: 2}, {X: 2, Y: 0}}, areas[1].Upper)
+
+	areas = stackSeries(series, true)
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 0.25}, {X: 1, Y: 1}, {X: 2, Y: 0}}, areas[0].Upper)
+	assert.Equal(t, plotter.XYs{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 0}}, areas[1].Upper)
+
+	assert.Empty(t, stackSeries(nil, true))
+}
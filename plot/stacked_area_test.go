@@ -0,0 +1,85 @@
+package plot_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/ark-pixel/plot"
+	"github.com/mlange-42/ark-pixel/window"
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleStackedArea() {
+
+	// Create a new model.
+	app := app.New()
+
+	// Limit the the simulation speed.
+	app.TPS = 30
+
+	// Create a stacked area plot.
+	// See below for the implementation of the RowObserver.
+	app.AddUISystem((&window.Window{}).
+		With(&plot.StackedArea{
+			Observer: &RowObserver{},
+		}))
+
+	// Add a termination system that ends the simulation.
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+
+	app.Run()
+
+	// Run the simulation.
+	// Due to the use of the OpenGL UI system, the model must be run via [window.Run].
+	// Comment out the code line above, and uncomment the next line to run this example stand-alone.
+
+	// window.Run(app)
+
+	// Output:
+}
+
+func TestStackedArea_Normalize(t *testing.T) {
+	area := plot.StackedArea{
+		Observer:  &RowObserver{},
+		Columns:   []string{"A", "C"},
+		Normalize: true,
+		MaxRows:   20,
+		YAxis:     plot.Axis{Format: plot.PercentFormat},
+	}
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&area))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "area.csv")
+	assert.Nil(t, area.ExportData(path))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 21, len(lines))
+	assert.Equal(t, "Tick,A,C", lines[0])
+}
+
+func TestStackedArea_PanicColumns(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.StackedArea{
+			Observer: &RowObserver{},
+			Columns:  []string{"A", "F"},
+		}))
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	assert.Panics(t, app.Run)
+}
@@ -0,0 +1,160 @@
+package plot
+
+import (
+	"image/color"
+
+	"golang.org/x/image/colornames"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// LegendPosition is the placement of a plot legend.
+type LegendPosition uint8
+
+const (
+	// LegendBottomRight places the legend in the bottom right corner of the plot.
+	LegendBottomRight LegendPosition = iota
+	// LegendBottomLeft places the legend in the bottom left corner of the plot.
+	LegendBottomLeft
+	// LegendTopRight places the legend in the top right corner of the plot.
+	LegendTopRight
+	// LegendTopLeft places the legend in the top left corner of the plot.
+	LegendTopLeft
+)
+
+// Style of plots.
+//
+// Use [LightStyle] or [DarkStyle] as a starting point for customization.
+// Zero-valued fields fall back to the values of [LightStyle].
+type Style struct {
+	Background color.Color        // Background color.
+	Foreground color.Color        // Color of text, axes and ticks.
+	GridColor  color.Color        // Color of grid lines.
+	Colors     []color.Color      // Color cycle for data series.
+	Font       font.Variant       // Font variant. One of "Mono", "Sans" and "Serif".
+	TitleSize  vg.Length          // Font size of the title.
+	LabelSize  vg.Length          // Font size of axis labels.
+	TickSize   vg.Length          // Font size of tick labels and legend entries.
+	LineWidth  vg.Length          // Line width of data series.
+	Dashes     [][]vg.Length      // Dash pattern cycle for line series. Optional, default solid lines only.
+	Markers    []draw.GlyphDrawer // Marker shape cycle for scatter plots.
+	MarkerSize vg.Length          // Marker radius for scatter plots.
+	Legend     LegendPosition     // Legend placement. Optional, default bottom right.
+}
+
+// LightStyle creates the default plot style, with a white background.
+func LightStyle() *Style {
+	return &Style{
+		Background: color.White,
+		Foreground: color.Black,
+		GridColor:  color.Gray{Y: 128},
+		Colors:     defaultColors,
+		Font:       "Mono",
+		TitleSize:  16,
+		LabelSize:  14,
+		TickSize:   12,
+		LineWidth:  vg.Points(1),
+		Markers:    []draw.GlyphDrawer{draw.CircleGlyph{}},
+		MarkerSize: vg.Points(2.5),
+	}
+}
+
+// DarkStyle creates a plot style with a dark background, matching the monitor drawers.
+func DarkStyle() *Style {
+	s := LightStyle()
+	s.Background = color.RGBA{0, 25, 10, 255}
+	s.Foreground = color.RGBA{200, 200, 200, 255}
+	s.GridColor = color.RGBA{50, 70, 60, 255}
+	s.Colors = []color.Color{
+		colornames.Deepskyblue,
+		colornames.Orange,
+		colornames.Limegreen,
+		colornames.Violet,
+		colornames.Tomato,
+		colornames.Turquoise,
+	}
+	return s
+}
+
+// resolve returns a copy of the style, with defaults for zero-valued fields.
+// A nil style results in [LightStyle].
+func (s *Style) resolve() *Style {
+	def := LightStyle()
+	if s == nil {
+		return def
+	}
+	r := *s
+	if r.Background == nil {
+		r.Background = def.Background
+	}
+	if r.Foreground == nil {
+		r.Foreground = def.Foreground
+	}
+	if r.GridColor == nil {
+		r.GridColor = def.GridColor
+	}
+	if len(r.Colors) == 0 {
+		r.Colors = def.Colors
+	}
+	if r.Font == "" {
+		r.Font = def.Font
+	}
+	if r.TitleSize <= 0 {
+		r.TitleSize = def.TitleSize
+	}
+	if r.LabelSize <= 0 {
+		r.LabelSize = def.LabelSize
+	}
+	if r.TickSize <= 0 {
+		r.TickSize = def.TickSize
+	}
+	if r.LineWidth <= 0 {
+		r.LineWidth = def.LineWidth
+	}
+	if len(r.Markers) == 0 {
+		r.Markers = def.Markers
+	}
+	if r.MarkerSize <= 0 {
+		r.MarkerSize = def.MarkerSize
+	}
+	return &r
+}
+
+// color returns the i-th color of the color cycle.
+func (s *Style) color(i int) color.Color {
+	return s.Colors[i%len(s.Colors)]
+}
+
+// lineStyle returns the line style for the i-th data series.
+func (s *Style) lineStyle(i int) draw.LineStyle {
+	sty := draw.LineStyle{
+		Color: s.color(i),
+		Width: s.LineWidth,
+	}
+	if len(s.Dashes) > 0 {
+		sty.Dashes = s.Dashes[i%len(s.Dashes)]
+	}
+	return sty
+}
+
+// glyphStyle returns the marker style for the i-th data series.
+func (s *Style) glyphStyle(i int) draw.GlyphStyle {
+	return draw.GlyphStyle{
+		Color:  s.color(i),
+		Radius: s.MarkerSize,
+		Shape:  s.Markers[i%len(s.Markers)],
+	}
+}
+
+// newLegend creates a legend in the given style.
+func newLegend(s *Style) plot.Legend {
+	l := plot.NewLegend()
+	l.TextStyle.Font.Variant = s.Font
+	l.TextStyle.Font.Size = s.TickSize
+	l.TextStyle.Color = s.Foreground
+	l.Top = s.Legend == LegendTopRight || s.Legend == LegendTopLeft
+	l.Left = s.Legend == LegendBottomLeft || s.Legend == LegendTopLeft
+	return l
+}
@@ -0,0 +1,60 @@
+package plot
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+func TestStyleResolve(t *testing.T) {
+	var s *Style
+	assert.Equal(t, LightStyle(), s.resolve())
+
+	s = &Style{
+		Background: color.Black,
+		LineWidth:  2,
+		Dashes:     [][]vg.Length{nil, {4, 2}},
+	}
+	r := s.resolve()
+	assert.Equal(t, color.Black, r.Background)
+	assert.Equal(t, color.Black, r.Foreground)
+	assert.Equal(t, vg.Length(2), r.LineWidth)
+	assert.Equal(t, vg.Length(12), r.TickSize)
+	assert.Nil(t, s.Foreground)
+
+	assert.Equal(t, r.Colors[1], r.color(len(r.Colors)+1))
+	assert.Nil(t, r.lineStyle(0).Dashes)
+	assert.Equal(t, []vg.Length{4, 2}, r.lineStyle(1).Dashes)
+	assert.Equal(t, vg.Length(2), r.lineStyle(1).Width)
+
+	r = (&Style{Markers: []draw.GlyphDrawer{draw.BoxGlyph{}, draw.TriangleGlyph{}}}).resolve()
+	assert.Equal(t, draw.TriangleGlyph{}, r.glyphStyle(3).Shape)
+	assert.Equal(t, vg.Points(2.5), r.glyphStyle(3).Radius)
+}
+
+func TestNewLegend(t *testing.T) {
+	l := newLegend(DarkStyle())
+	assert.False(t, l.Top)
+	assert.False(t, l.Left)
+	assert.Equal(t, DarkStyle().Foreground, l.TextStyle.Color)
+
+	l = newLegend(&Style{Legend: LegendTopLeft})
+	assert.True(t, l.Top)
+	assert.True(t, l.Left)
+}
+
+func TestSetLabels(t *testing.T) {
+	p := plot.New()
+	s := DarkStyle()
+	setLabels(p, Labels{Title: "Title", X: "X", Y: "Y"}, s)
+
+	assert.Equal(t, s.Background, p.BackgroundColor)
+	assert.Equal(t, s.Foreground, p.Title.TextStyle.Color)
+	assert.Equal(t, s.Foreground, p.X.Tick.Label.Color)
+	assert.Equal(t, s.Foreground, p.Y.Color)
+	assert.Equal(t, "Y", p.Y.Label.Text)
+}
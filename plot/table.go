@@ -0,0 +1,342 @@
+package plot
+
+import (
+	"fmt"
+	"math"
+
+	px "github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/gopxl/pixel/v2/ext/text"
+	"github.com/mlange-42/ark-pixel/internal/util"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Margin around tables, and gap between table columns, in pixels.
+const (
+	tableMargin = 10.0
+	tableGap    = 20.0
+)
+
+// Opacity of highlighted table rows.
+const tableHighlightOpacity = 0.3
+
+// Table drawer.
+//
+// Shows data as a column-aligned text table, drawn directly with OpenGL like the monitor drawers.
+// Takes the complete table from a table observer when drawing after an update,
+// or accumulates a history of rows from a row observer, with a Tick column and the newest row first.
+//
+// Rows can be sorted by clicking column headers, cycling through ascending, descending and unsorted.
+// The view can be scrolled using arrow keys, page up/down, home/end or the mouse wheel.
+// The row under the cursor is highlighted, as well as rows selected by Highlight.
+type Table struct {
+	Observer       observer.Table           // Observer providing the complete table on every update. Alternative to Row.
+	Row            observer.Row             // Observer providing a row per update, accumulated into a history. Alternative to Observer.
+	Columns        []string                 // Columns to show, by name. Optional, default all.
+	SortBy         string                   // Column to sort rows by initially. Optional, default observer order.
+	SortDescending bool                     // Sorts by SortBy in descending order. Optional.
+	Format         TickFormat               // Number format of cells. Optional, default plain numbers.
+	Formatter      func(v float64) string   // Custom number formatter. Optional, overrides Format.
+	Highlight      func(row []float64) bool // Selects rows to highlight, given the values of the shown columns, with the tick first for Row. Optional.
+	UpdateInterval int                      // Interval for getting data from the the row observer, in model ticks. Optional.
+	MaxRows        int                      // Maximum number of rows to keep from the row observer. Zero means unlimited. Optional.
+	Style          *Style                   // Style (colors). Fonts and line widths are ignored. Optional, default light style.
+	ExportPath     string                   // File for exporting the data with Ctrl+E, format by extension. Optional, default time-stamped CSV.
+
+	indices   []int
+	headers   []string
+	rows      [][]float64
+	buffer    []float64
+	order     []int
+	sortCol   int
+	sort      tableSort
+	sorted    bool
+	dirty     bool // Whether the data of the table observer needs to be fetched.
+	formatter func(float64) string
+	scroll    int
+	step      int64
+
+	style    *Style
+	drawer   *imdraw.IMDraw
+	text     *text.Text
+	helpText *text.Text
+	cells    [][]string
+	columnX  [][2]float64 // Left and right edge per column, from the last draw.
+	headerY  [2]float64   // Bottom and top of the header, from the last draw.
+	visible  int          // Number of visible rows, from the last draw.
+}
+
+// Initialize the drawer.
+func (t *Table) Initialize(w *ecs.World, _ *opengl.Window) {
+	if (t.Observer == nil) == (t.Row == nil) {
+		panic("table requires exactly one of Observer and Row")
+	}
+
+	var headers []string
+	if t.Row != nil {
+		t.Row.Initialize(w)
+		headers = t.Row.Header()
+	} else {
+		t.Observer.Initialize(w)
+		headers = t.Observer.Header()
+	}
+
+	if len(t.Columns) == 0 {
+		t.indices = make([]int, len(headers))
+		for i := range t.indices {
+			t.indices[i] = i
+		}
+	} else {
+		t.indices = make([]int, len(t.Columns))
+		var ok bool
+		for i, col := range t.Columns {
+			t.indices[i], ok = find(headers, col)
+			if !ok {
+				panic(fmt.Sprintf("column '%s' not found", col))
+			}
+		}
+	}
+
+	t.headers = make([]string, 0, len(t.indices)+1)
+	if t.Row != nil {
+		t.headers = append(t.headers, "Tick")
+	}
+	for _, idx := range t.indices {
+		t.headers = append(t.headers, headers[idx])
+	}
+
+	t.sortCol, t.sort = -1, sortNone
+	if t.SortBy != "" {
+		var ok bool
+		t.sortCol, ok = find(t.headers, t.SortBy)
+		if !ok {
+			panic(fmt.Sprintf("sort column '%s' not found", t.SortBy))
+		}
+		t.sort = sortAscending
+		if t.SortDescending {
+			t.sort = sortDescending
+		}
+	}
+
+	t.formatter = (&Axis{Format: t.Format, Formatter: t.Formatter}).formatter()
+	t.style = t.Style.resolve()
+	t.drawer = imdraw.New(nil)
+	t.text = text.New(px.V(0, 0), util.DefaultFont)
+	t.text.Color = t.style.Foreground
+	t.helpText = text.New(px.V(0, 0), util.DefaultFont)
+	t.helpText.Color = t.style.Foreground
+	t.rows = nil
+	t.scroll = 0
+	t.step = 0
+	t.sorted = false
+	t.dirty = false
+}
+
+// Update the drawer.
+func (t *Table) Update(w *ecs.World) {
+	if t.Row != nil {
+		t.updateHistory(w)
+		return
+	}
+	t.Observer.Update(w)
+	t.dirty = true
+}
+
+// updateData copies the shown columns of the table observer.
+func (t *Table) updateData(w *ecs.World) {
+	data := t.Observer.Values(w)
+	cols := len(t.indices)
+	t.buffer = t.buffer[:0]
+	for _, row := range data {
+		for _, idx := range t.indices {
+			t.buffer = append(t.buffer, row[idx])
+		}
+	}
+	t.rows = t.rows[:0]
+	for i := range data {
+		t.rows = append(t.rows, t.buffer[i*cols:(i+1)*cols:(i+1)*cols])
+	}
+	t.sorted = false
+}
+
+// updateHistory appends a row from the row observer to the history.
+func (t *Table) updateHistory(w *ecs.World) {
+	t.Row.Update(w)
+	if t.UpdateInterval <= 1 || t.step%int64(t.UpdateInterval) == 0 {
+		values := t.Row.Values(w)
+		row := make([]float64, 0, len(t.indices)+1)
+		row = append(row, float64(t.step))
+		for _, idx := range t.indices {
+			row = append(row, values[idx])
+		}
+		t.rows = append(t.rows, row)
+		if t.MaxRows > 0 && len(t.rows) > t.MaxRows {
+			t.rows = t.rows[len(t.rows)-t.MaxRows:]
+		}
+		t.sorted = false
+	}
+	t.step++
+}
+
+// UpdateInputs handles input events of the previous frame update.
+func (t *Table) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	if exportPressed(win) {
+		exportWindow(t.ExportPath, t.ExportData)
+	}
+
+	switch {
+	case win.JustPressed(px.KeyDown):
+		t.scroll++
+	case win.JustPressed(px.KeyUp):
+		t.scroll--
+	case win.JustPressed(px.KeyPageDown):
+		t.scroll += max(t.visible-1, 1)
+	case win.JustPressed(px.KeyPageUp):
+		t.scroll -= max(t.visible-1, 1)
+	case win.JustPressed(px.KeyHome):
+		t.scroll = 0
+	case win.JustPressed(px.KeyEnd):
+		t.scroll = len(t.rows)
+	}
+	if scr := win.MouseScroll(); scr.Y != 0 {
+		t.scroll -= int(scr.Y)
+	}
+	t.scroll, _, _ = scrollRange(t.scroll, len(t.rows), t.visible)
+
+	if win.JustPressed(px.MouseButtonLeft) {
+		mouse := win.MousePosition()
+		if mouse.Y < t.headerY[0] || mouse.Y > t.headerY[1] {
+			return
+		}
+		for i, x := range t.columnX {
+			if mouse.X < x[0]-tableGap/2 || mouse.X > x[1]+tableGap/2 {
+				continue
+			}
+			if i != t.sortCol {
+				t.sortCol, t.sort = i, sortNone
+			}
+			t.sort = t.sort.next()
+			t.sorted = false
+			break
+		}
+	}
+}
+
+// Draw the drawer.
+func (t *Table) Draw(w *ecs.World, win *opengl.Window) {
+	if t.dirty {
+		t.updateData(w)
+		t.dirty = false
+	}
+	if !t.sorted || len(t.order) != len(t.rows) {
+		t.order = rowOrder(t.order, len(t.rows), t.Row != nil)
+		if t.sort != sortNone {
+			sortRows(t.order, t.rows, t.sortCol, t.sort == sortDescending)
+		}
+		t.sorted = true
+	}
+
+	width := win.Canvas().Bounds().W()
+	height := win.Canvas().Bounds().H()
+	lineHeight := util.DefaultFont.LineHeight()
+
+	win.Clear(t.style.Background)
+	t.text.Clear()
+	t.helpText.Clear()
+
+	top := height - tableMargin
+	t.headerY = [2]float64{top - 1.5*lineHeight, top}
+	rowsTop := t.headerY[0] - 0.5*lineHeight
+	footer := tableMargin + 1.5*lineHeight
+	t.visible = max(int((rowsTop-footer)/lineHeight), 0)
+
+	var first, last int
+	t.scroll, first, last = scrollRange(t.scroll, len(t.rows), t.visible)
+
+	// Format visible cells and measure columns.
+	t.cells = t.cells[:0]
+	for _, r := range t.order[first:last] {
+		row := make([]string, len(t.headers))
+		for c, v := range t.rows[r] {
+			row[c] = formatCell(v, t.formatter)
+		}
+		t.cells = append(t.cells, row)
+	}
+	t.columnX = t.columnX[:0]
+	x := tableMargin
+	for c, header := range t.headers {
+		if c == t.sortCol {
+			header += t.sort.indicator()
+		}
+		w := t.text.BoundsOf(header).W()
+		for _, row := range t.cells {
+			w = math.Max(w, t.text.BoundsOf(row[c]).W())
+		}
+		t.columnX = append(t.columnX, [2]float64{x, x + w})
+		t.textAt(header, x+w, t.headerY[0]+0.5*lineHeight)
+		x += w + tableGap
+	}
+	right := math.Min(x-tableGap+tableMargin, width)
+
+	// Row highlights.
+	dr := t.drawer
+	mouse := win.MousePosition()
+	for i, r := range t.order[first:last] {
+		y := rowsTop - float64(i+1)*lineHeight
+		switch {
+		case mouse.Y >= y && mouse.Y < y+lineHeight && mouse.X <= right:
+			dr.Color = t.style.GridColor
+		case t.Highlight != nil && t.Highlight(t.rows[r]):
+			dr.Color = bandColor(t.style.color(0), tableHighlightOpacity)
+		default:
+			continue
+		}
+		dr.Push(px.V(0, y), px.V(right, y+lineHeight))
+		dr.Rectangle(0)
+	}
+
+	// Cells, right-aligned.
+	for i, row := range t.cells {
+		y := rowsTop - float64(i+1)*lineHeight + lineHeight/3
+		for c, cell := range row {
+			t.textAt(cell, t.columnX[c][1], y)
+		}
+	}
+
+	dr.Color = t.style.Foreground
+	dr.Push(px.V(tableMargin, t.headerY[0]), px.V(right-tableMargin, t.headerY[0]))
+	dr.Line(1)
+
+	dr.Draw(win)
+	dr.Clear()
+	t.text.Draw(win, px.IM)
+
+	shown := "no rows"
+	if last > first {
+		shown = fmt.Sprintf("rows %d-%d of %d", first+1, last, len(t.rows))
+	}
+	_, _ = fmt.Fprintf(t.helpText, "Showing %s. Sort by clicking headers, scroll with arrows, page up/down or mouse wheel.", shown)
+	t.helpText.Draw(win, px.IM.Moved(px.V(tableMargin, tableMargin)))
+}
+
+// ExportData writes the current data of the table to a file, with column headers from the observer.
+// Rows are exported in the order of the observer, independent of sorting.
+// The file format is selected by the extension. Supported are csv and json.
+func (t *Table) ExportData(path string) error {
+	columns := make([]dataColumn, len(t.headers))
+	for c, name := range t.headers {
+		columns[c] = dataColumn{Name: name, Values: make([]float64, len(t.rows))}
+		for r, row := range t.rows {
+			columns[c].Values[r] = row[c]
+		}
+	}
+	return exportData(path, columns)
+}
+
+// textAt writes text right-aligned to the given position.
+func (t *Table) textAt(s string, right, y float64) {
+	t.text.Dot = px.V(right-t.text.BoundsOf(s).W(), y)
+	_, _ = fmt.Fprint(t.text, s)
+}
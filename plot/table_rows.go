@@ -0,0 +1,79 @@
+package plot
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// tableSort is the sort state of a table column.
+type tableSort uint8
+
+const (
+	sortNone tableSort = iota
+	sortAscending
+	sortDescending
+)
+
+// next returns the sort state after clicking a column header: ascending, descending, unsorted.
+func (s tableSort) next() tableSort {
+	return (s + 1) % 3
+}
+
+// indicator returns the suffix of a column header for the sort state.
+func (s tableSort) indicator() string {
+	switch s {
+	case sortAscending:
+		return " ^"
+	case sortDescending:
+		return " v"
+	default:
+		return ""
+	}
+}
+
+// rowOrder returns the indices of n rows, in reverse order if reversed is true.
+// Appends to dst[:0].
+func rowOrder(dst []int, n int, reversed bool) []int {
+	dst = dst[:0]
+	for i := range n {
+		if reversed {
+			dst = append(dst, n-1-i)
+		} else {
+			dst = append(dst, i)
+		}
+	}
+	return dst
+}
+
+// sortRows sorts row indices by the values of a column.
+// NaN values are sorted last in both directions.
+// The sort is stable, so that rows with equal values keep their order.
+func sortRows(order []int, rows [][]float64, col int, descending bool) {
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := rows[order[i]][col], rows[order[j]][col]
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return !math.IsNaN(a) && math.IsNaN(b)
+		}
+		if descending {
+			return a > b
+		}
+		return a < b
+	})
+}
+
+// scrollRange clamps the scroll position for the number of rows and visible rows,
+// and returns it together with the first and last (exclusive) visible row.
+func scrollRange(scroll, rows, visible int) (clamped, first, last int) {
+	visible = max(visible, 0)
+	clamped = max(min(scroll, rows-visible), 0)
+	return clamped, clamped, min(clamped+visible, rows)
+}
+
+// formatCell formats a table cell, with the formatter if it is not nil.
+func formatCell(v float64, formatter func(float64) string) string {
+	if formatter != nil {
+		return formatter(v)
+	}
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
@@ -0,0 +1,70 @@
+package plot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableSort(t *testing.T) {
+	assert.Equal(t, sortAscending, sortNone.next())
+	assert.Equal(t, sortDescending, sortAscending.next())
+	assert.Equal(t, sortNone, sortDescending.next())
+
+	assert.Equal(t, "", sortNone.indicator())
+	assert.Equal(t, " ^", sortAscending.indicator())
+	assert.Equal(t, " v", sortDescending.indicator())
+}
+
+func TestRowOrder(t *testing.T) {
+	order := rowOrder(nil, 3, false)
+	assert.Equal(t, []int{0, 1, 2}, order)
+
+	order = rowOrder(order, 4, true)
+	assert.Equal(t, []int{3, 2, 1, 0}, order)
+
+	assert.Empty(t, rowOrder(order, 0, false))
+}
+
+func TestSortRows(t *testing.T) {
+	rows := [][]float64{
+		{0, 3},
+		{1, math.NaN()},
+		{2, 1},
+		{3, 3},
+		{4, 2},
+	}
+
+	order := rowOrder(nil, len(rows), false)
+	sortRows(order, rows, 1, false)
+	assert.Equal(t, []int{2, 4, 0, 3, 1}, order)
+
+	order = rowOrder(order, len(rows), false)
+	sortRows(order, rows, 1, true)
+	assert.Equal(t, []int{0, 3, 4, 2, 1}, order)
+}
+
+func TestScrollRange(t *testing.T) {
+	scroll, first, last := scrollRange(0, 100, 10)
+	assert.Equal(t, []int{0, 0, 10}, []int{scroll, first, last})
+
+	scroll, first, last = scrollRange(95, 100, 10)
+	assert.Equal(t, []int{90, 90, 100}, []int{scroll, first, last})
+
+	scroll, first, last = scrollRange(-5, 100, 10)
+	assert.Equal(t, []int{0, 0, 10}, []int{scroll, first, last})
+
+	scroll, first, last = scrollRange(3, 5, 10)
+	assert.Equal(t, []int{0, 0, 5}, []int{scroll, first, last})
+
+	scroll, first, last = scrollRange(3, 5, -1)
+	assert.Equal(t, []int{3, 3, 3}, []int{scroll, first, last})
+}
+
+func TestFormatCell(t *testing.T) {
+	assert.Equal(t, "1.5", formatCell(1.5, nil))
+	assert.Equal(t, "3.14159", formatCell(math.Pi, nil))
+	assert.Equal(t, "NaN", formatCell(math.NaN(), nil))
+	assert.Equal(t, "25%", formatCell(0.25, formatPercent))
+}
@@ -0,0 +1,114 @@
+package plot_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/ark-pixel/plot"
+	"github.com/mlange-42/ark-pixel/window"
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleTable() {
+
+	// Create a new model.
+	app := app.New()
+
+	// Limit the the simulation speed.
+	app.TPS = 30
+
+	// Create a table drawer.
+	// See below for the implementation of the TableObserver.
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Table{
+			Observer: &TableObserver{},
+			SortBy:   "A", // Optional initial sort column.
+		}))
+
+	// Add a termination system that ends the simulation.
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+
+	app.Run()
+
+	// Run the simulation.
+	// Due to the use of the OpenGL UI system, the model must be run via [window.Run].
+	// Comment out the code line above, and uncomment the next line to run this example stand-alone.
+
+	// window.Run(app)
+
+	// Output:
+}
+
+func TestTable(t *testing.T) {
+	table := plot.Table{
+		Observer:       &TableObserver{},
+		Columns:        []string{"X", "B"},
+		SortBy:         "B",
+		SortDescending: true,
+		Format:         plot.SIFormat,
+		Highlight:      func(row []float64) bool { return row[0] > 10 },
+	}
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&table))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "table.csv")
+	assert.Nil(t, table.ExportData(path))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 26, len(lines))
+	assert.Equal(t, "X,B", lines[0])
+}
+
+func TestTable_Row(t *testing.T) {
+	table := plot.Table{
+		Row:     &RowObserver{},
+		MaxRows: 20,
+	}
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&table))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "table.csv")
+	assert.Nil(t, table.ExportData(path))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 21, len(lines))
+	assert.Equal(t, "Tick,A,B,C", lines[0])
+}
+
+func TestTable_Panic(t *testing.T) {
+	for _, table := range []plot.Table{
+		{},
+		{Observer: &TableObserver{}, Row: &RowObserver{}},
+		{Observer: &TableObserver{}, Columns: []string{"F"}},
+		{Observer: &TableObserver{}, SortBy: "F"},
+	} {
+		app := app.New()
+		app.TPS = 300
+		app.AddUISystem((&window.Window{}).With(&table))
+
+		app.AddSystem(&system.FixedTermination{
+			Steps: 100,
+		})
+		assert.Panics(t, app.Run)
+	}
+}
@@ -2,45 +2,109 @@ package plot
 
 import (
 	"fmt"
-	"image/color"
 
-	pixel "github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
-	"gonum.org/v1/plot/vg/draw"
-	"gonum.org/v1/plot/vg/vgimg"
 )
 
 // TimeSeries plot drawer.
 //
 // Creates a line series per column of the observer.
 // Adds one row to the data per update.
+//
+// X values are the drawer's update steps by default.
+// Alternatively, they can be taken from a column of the observer (X),
+// from the model tick (ModelTick), or from a custom function (XFunc), e.g. for model time.
+// Only one of these options can be used.
+//
+// Events pushed into the [Annotations] resource by systems are shown as vertical lines with Events.
+// Static vertical markers and horizontal reference lines can be configured with VLines and HLines.
+//
+// By default, plots are rendered with gonum/plot.
+// With Native, plots are drawn directly with OpenGL, which is much faster
+// and suitable for fast-running models, but with simpler styling.
 type TimeSeries struct {
-	Observer       observer.Row // Observer providing a data row per update.
-	Columns        []string     // Columns to show, by name. Optional, default all.
-	UpdateInterval int          // Interval for getting data from the the observer, in model ticks. Optional.
-	Labels         Labels       // Labels for plot and axes. Optional.
-	MaxRows        int          // Maximum number of rows to keep. Zero means unlimited. Optional.
-
-	indices []int
-	headers []string
-	series  []plotter.XYs
-	scale   float64
-	step    int64
+	Observer       observer.Row               // Observer providing a data row per update.
+	Columns        []string                   // Columns to show, by name. Optional, default all but X.
+	Y2             []string                   // Columns to plot against a secondary Y axis on the right, by name. Must be among the shown columns. Optional.
+	Bands          []Band                     // Shaded bands around lines, e.g. for uncertainty ranges. Optional.
+	Events         bool                       // Shows events from the Annotations resource as vertical lines. Optional.
+	VLines         []Marker                   // Vertical marker lines at X values, e.g. for known interventions. Optional.
+	HLines         []Marker                   // Horizontal reference lines at Y values, e.g. for thresholds. Optional.
+	X              string                     // Column to use as X values. Optional, default update step.
+	ModelTick      bool                       // Uses the model tick from resource.Tick as X values. Optional.
+	XFunc          func(w *ecs.World) float64 // Function providing X values, e.g. model time from a resource. Optional.
+	UpdateInterval int                        // Interval for getting data from the the observer, in model ticks. Optional.
+	Labels         Labels                     // Labels for plot and axes. Optional.
+	Style          *Style                     // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	XAxis          Axis                       // X axis configuration (scale, tick format, grid, ...). Optional.
+	YAxis          Axis                       // Y axis configuration (scale, tick format, grid, ...). Optional.
+	Y2Axis         Axis                       // Secondary Y axis configuration. Only the tick format is used, the scale is always linear. Optional.
+	MaxRows        int                        // Maximum number of rows to keep. Zero means unlimited. Optional.
+	Window         float64                    // Rolling window to keep, in units of X (i.e. ticks by default). Zero means unlimited. Optional.
+	Downsampling   Downsampling               // Downsampling strategy to keep the full X range with a bounded number of points. Optional, default none.
+	MaxPoints      int                        // Number of points per series to keep with downsampling. Optional, default 1000.
+	Async          bool                       // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	Native         bool                       // Draws directly with OpenGL instead of rendering with gonum/plot. Ignores axis configuration. Optional.
+	SavePath       string                     // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+	ExportPath     string                     // File for exporting the data with Ctrl+E, format by extension. Optional, default time-stamped CSV.
+
+	indices  []int
+	y2       []bool
+	bands    []bandColumns
+	bandOf   []int
+	xIndex   int
+	xName    string
+	tickRes  ecs.Resource[resource.Tick]
+	events   ecs.Resource[Annotations]
+	nEvents  int
+	markers  []Marker
+	headers  []string
+	series   []plotter.XYs
+	sampler  downsampler
+	scale    float64
+	style    *Style
+	step     int64
+	renderer plotRenderer
+	native   nativePlot
+	names    []string
+	visible  []plotter.XYs
+	buffers  []plotter.XYs
+	shaded   []*bandPlotter
 }
 
 // append a y value to each series, with a common x value.
+// Drops rows outside of MaxRows and Window, and applies downsampling.
 func (t *TimeSeries) append(x float64, values []float64) {
+	if len(t.series) == 0 {
+		return
+	}
 	for i := 0; i < len(t.series); i++ {
 		t.series[i] = append(t.series[i], plotter.XY{X: x, Y: values[i]})
-		if t.MaxRows > 0 && len(t.series[i]) > t.MaxRows {
-			t.series[i] = t.series[i][len(t.series[i])-t.MaxRows:]
+	}
+
+	rows := len(t.series[0])
+	start := 0
+	if t.MaxRows > 0 && rows > t.MaxRows {
+		start = rows - t.MaxRows
+	}
+	if t.Window > 0 {
+		for start < rows-1 && t.series[0][start].X < x-t.Window {
+			start++
 		}
 	}
+	if start > 0 {
+		for i := range t.series {
+			t.series[i] = t.series[i][start:]
+		}
+	}
+
+	t.sampler.Apply(t.series, t.indices)
 }
 
 // Initialize the drawer.
@@ -49,10 +113,15 @@ func (t *TimeSeries) Initialize(w *ecs.World, _ *opengl.Window) {
 
 	t.headers = t.Observer.Header()
 
+	t.initX(w)
+	t.bands = resolveBands(t.headers, t.Bands)
+
 	if len(t.Columns) == 0 {
-		t.indices = make([]int, len(t.headers))
-		for i := 0; i < len(t.indices); i++ {
-			t.indices[i] = i
+		t.indices = make([]int, 0, len(t.headers))
+		for i := range t.headers {
+			if i != t.xIndex && !isBandBound(t.bands, i) {
+				t.indices = append(t.indices, i)
+			}
 		}
 	} else {
 		t.indices = make([]int, len(t.Columns))
@@ -66,54 +135,247 @@ func (t *TimeSeries) Initialize(w *ecs.World, _ *opengl.Window) {
 	}
 
 	t.series = make([]plotter.XYs, len(t.headers))
+	t.y2 = secondaryFlags(t.headers, t.indices, t.Y2)
+	t.bandOf = bandsByLine(t.headers, t.indices, t.bands)
+
+	t.events = ecs.NewResource[Annotations](w)
+	t.nEvents = 0
 
 	t.scale = calcScaleCorrection()
+	t.style = t.Style.resolve()
 	t.step = 0
+	t.sampler = downsampler{Method: t.Downsampling, MaxPoints: t.MaxPoints}
+	t.renderer = plotRenderer{}
+
+	if t.Native {
+		t.native = newNativePlot(t.style)
+		t.names = make([]string, len(t.indices))
+		t.visible = make([]plotter.XYs, len(t.indices))
+		t.buffers = make([]plotter.XYs, len(t.indices))
+		t.shaded = make([]*bandPlotter, len(t.indices))
+		for i, idx := range t.indices {
+			t.names[i] = t.headers[idx]
+			if t.y2[i] {
+				t.names[i] = secondaryName(t.names[i])
+			}
+		}
+	}
 }
 
 // Update the drawer.
 func (t *TimeSeries) Update(w *ecs.World) {
 	t.Observer.Update(w)
 	if t.UpdateInterval <= 1 || t.step%int64(t.UpdateInterval) == 0 {
-		t.append(float64(t.step), t.Observer.Values(w))
+		values := t.Observer.Values(w)
+		t.append(t.xValue(w, values), values)
+		t.renderer.Invalidate()
+	}
+	if n := len(t.eventMarkers()); n != t.nEvents {
+		t.nEvents = n
+		t.renderer.Invalidate()
 	}
 	t.step++
 }
 
 // UpdateInputs handles input events of the previous frame update.
-func (t *TimeSeries) UpdateInputs(_ *ecs.World, _ *opengl.Window) {}
+func (t *TimeSeries) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	if !t.Native {
+		t.renderer.HandleInputs(win, t.scale)
+	}
+	if savePressed(win) {
+		saveWindow(win, t.SavePath, t.scale, t.SaveAs)
+	}
+	if exportPressed(win) {
+		exportWindow(t.ExportPath, t.ExportData)
+	}
+}
 
 // Draw the drawer.
 func (t *TimeSeries) Draw(_ *ecs.World, win *opengl.Window) {
-	width := win.Canvas().Bounds().W()
-	height := win.Canvas().Bounds().H()
+	if t.Native {
+		for i, idx := range t.indices {
+			t.visible[i] = t.series[idx]
+			t.shaded[i] = t.bandFor(i, t.shaded[i])
+		}
+		secondary := secondaryFor(t.visible, t.y2, t.Labels.Y2, t.Y2Axis)
+		if secondary != nil {
+			for i, y2 := range t.y2 {
+				if !y2 {
+					continue
+				}
+				t.buffers[i] = secondary.transform(t.buffers[i], t.visible[i])
+				t.visible[i] = t.buffers[i]
+				if band := t.shaded[i]; band != nil {
+					band.Lower = secondary.transform(band.Lower, band.Lower)
+					band.Upper = secondary.transform(band.Upper, band.Upper)
+				}
+			}
+		}
+		t.native.Draw(win, t.labels(), t.names, t.visible, t.shaded, secondary, t.vLines(), t.HLines)
+		return
+	}
+	t.renderer.Draw(win, t.scale, t.Async, t.buildFigure)
+}
 
-	c := vgimg.New(vg.Points(width*t.scale)-10, vg.Points(height*t.scale)-10)
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (t *TimeSeries) SaveAs(path string, width, height vg.Length) error {
+	if t.Native {
+		return saveFigure(t.buildFigure(), path, width, height)
+	}
+	return t.renderer.Save(path, width, height)
+}
 
+// ExportData writes the current data of the plot to a file, with column headers from the observer.
+// The X column is named after the X source, or "Tick" for update steps.
+// The file format is selected by the extension. Supported are csv and json.
+func (t *TimeSeries) ExportData(path string) error {
+	columns := make([]dataColumn, 0, len(t.indices)+1)
+	x := dataColumn{Name: t.xName}
+	if x.Name == "" {
+		x.Name = "Tick"
+	}
+	if len(t.series) > 0 {
+		x.Values = make([]float64, len(t.series[0]))
+		for i, pt := range t.series[0] {
+			x.Values[i] = pt.X
+		}
+	}
+	columns = append(columns, x)
+	for _, idx := range t.indices {
+		col := dataColumn{Name: t.headers[idx], Values: make([]float64, len(t.series[idx]))}
+		for i, pt := range t.series[idx] {
+			col.Values[i] = pt.Y
+		}
+		columns = append(columns, col)
+	}
+	return exportData(path, columns)
+}
+
+func (t *TimeSeries) buildFigure() *figure {
 	p := plot.New()
-	setLabels(p, t.Labels)
+	setLabels(p, t.labels(), t.style)
 
 	p.X.Tick.Marker = removeLastTicks{}
+	setAxes(p, t.XAxis, t.YAxis, t.style)
 
-	p.Legend = plot.NewLegend()
-	p.Legend.TextStyle.Font.Variant = "Mono"
+	series := make([]plotter.XYs, len(t.indices))
+	for i, idx := range t.indices {
+		series[i] = t.series[idx]
+	}
+	fig := figure{plot: p}
+	fig.secondary = secondaryFor(series, t.y2, t.Labels.Y2, t.Y2Axis)
+
+	p.Legend = newLegend(t.style)
 
+	bands := make([]*bandPlotter, len(t.indices))
+	for i := range t.indices {
+		if bands[i] = t.bandFor(i, nil); bands[i] != nil {
+			addBand(&fig, bands[i], t.y2[i])
+		}
+	}
+	if hLines := t.HLines; len(hLines) > 0 {
+		p.Add(newMarkerLines(hLines, true, t.style))
+	}
+	if vLines := t.vLines(); len(vLines) > 0 {
+		p.Add(newMarkerLines(vLines, false, t.style))
+	}
 	for i, idx := range t.indices {
-		lines, err := plotter.NewLine(t.series[idx])
-		if err != nil {
-			panic(err)
+		fig.series = append(fig.series, addLine(&fig, series[i], t.headers[idx], t.y2[i], t.style.lineStyle(i), bands[i]))
+	}
+
+	return &fig
+}
+
+// bandFor calculates the band of the i-th shown column, re-using dst if not nil.
+// Returns nil if the column has no band.
+func (t *TimeSeries) bandFor(i int, dst *bandPlotter) *bandPlotter {
+	if t.bandOf[i] < 0 {
+		return nil
+	}
+	b := &t.bands[t.bandOf[i]]
+	if dst == nil {
+		dst = &bandPlotter{}
+	}
+	line := t.series[b.Line]
+	dst.Lower, dst.Upper = b.bounds(len(line),
+		func(row int) float64 { return line[row].X },
+		func(row, col int) float64 { return t.series[col][row].Y },
+		dst.Lower, dst.Upper)
+	dst.Color = bandColor(t.style.color(i), b.Opacity)
+	return dst
+}
+
+// initX sets up the source of X values.
+func (t *TimeSeries) initX(w *ecs.World) {
+	sources := 0
+	for _, set := range []bool{t.X != "", t.ModelTick, t.XFunc != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		panic("only one of X, ModelTick and XFunc can be used")
+	}
+
+	t.xIndex = -1
+	t.xName = ""
+	switch {
+	case t.X != "":
+		var ok bool
+		t.xIndex, ok = find(t.headers, t.X)
+		if !ok {
+			panic(fmt.Sprintf("x column '%s' not found", t.X))
 		}
-		lines.Color = defaultColors[i%len(defaultColors)]
-		p.Add(lines)
-		p.Legend.Add(t.headers[idx], lines)
+		t.xName = t.X
+	case t.ModelTick:
+		t.tickRes = ecs.NewResource[resource.Tick](w)
+		t.xName = "Tick"
+	case t.XFunc != nil:
+		t.xName = "X"
+	}
+}
+
+// xValue returns the X value for the current update.
+func (t *TimeSeries) xValue(w *ecs.World, values []float64) float64 {
+	switch {
+	case t.xIndex >= 0:
+		return values[t.xIndex]
+	case t.ModelTick:
+		return float64(t.tickRes.Get().Tick)
+	case t.XFunc != nil:
+		return t.XFunc(w)
+	default:
+		return float64(t.step)
 	}
+}
 
-	win.Clear(color.White)
-	p.Draw(draw.New(c))
+// labels returns the plot labels, with the X axis labelled after the X source if not given.
+// Custom X functions have no name and keep the label empty.
+func (t *TimeSeries) labels() Labels {
+	l := t.Labels
+	if l.X == "" && t.XFunc == nil {
+		l.X = t.xName
+	}
+	return l
+}
 
-	img := c.Image()
-	picture := pixel.PictureDataFromImage(img)
+// eventMarkers returns the events from the [Annotations] resource,
+// or nil if events are not shown or the resource is not present.
+func (t *TimeSeries) eventMarkers() []Marker {
+	if !t.Events || !t.events.Has() {
+		return nil
+	}
+	return t.events.Get().Events
+}
 
-	sprite := pixel.NewSprite(picture, picture.Bounds())
-	sprite.Draw(win, pixel.IM.Moved(pixel.V(picture.Rect.W()/2.0+5, picture.Rect.H()/2.0+5)))
+// vLines returns the static vertical lines, followed by the events.
+// Re-uses a buffer if there are events.
+func (t *TimeSeries) vLines() []Marker {
+	events := t.eventMarkers()
+	if len(events) == 0 {
+		return t.VLines
+	}
+	t.markers = append(append(t.markers[:0], t.VLines...), events...)
+	return t.markers
 }
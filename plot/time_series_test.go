@@ -2,14 +2,20 @@ package plot_test
 
 import (
 	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mlange-42/ark-pixel/plot"
 	"github.com/mlange-42/ark-pixel/window"
 	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark-tools/system"
 	"github.com/mlange-42/ark/ecs"
 	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/vg"
 )
 
 func ExampleTimeSeries() {
@@ -59,6 +65,318 @@ func TestTimeSeries_Columns(t *testing.T) {
 	app.Run()
 }
 
+func TestTimeSeries_Async(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Async:    true,
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestTimeSeries_Native(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Columns:  []string{"A", "C"},
+			Labels:   plot.Labels{Title: "Title", X: "X", Y: "Y"},
+			Native:   true,
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestTimeSeries_Style(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Style:    plot.DarkStyle(),
+		}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Style:    plot.DarkStyle(),
+			Native:   true,
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestTimeSeries_SaveAs(t *testing.T) {
+	ts := plot.TimeSeries{
+		Observer: &RowObserver{},
+	}
+
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&ts))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "plot.svg")
+	assert.Nil(t, ts.SaveAs(path, 4*vg.Inch, 3*vg.Inch))
+	assert.FileExists(t, path)
+}
+
+func TestTimeSeries_X(t *testing.T) {
+	ts := plot.TimeSeries{
+		Observer: &RowObserver{},
+		X:        "A",
+	}
+
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&ts))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "data.csv")
+	assert.Nil(t, ts.ExportData(path))
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "A,B,C\n"))
+}
+
+func TestTimeSeries_ModelTick(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer:  &RowObserver{},
+			ModelTick: true,
+			XAxis:     plot.Axis{Format: plot.TimeOfDayFormat, TickDuration: time.Hour},
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestTimeSeries_XFunc(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			XFunc: func(w *ecs.World) float64 {
+				// Model time in years, with 365 ticks per year.
+				return float64(ecs.GetResource[resource.Tick](w).Tick) / 365
+			},
+			Labels: plot.Labels{X: "Years"},
+			Native: true,
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestTimeSeries_PanicX(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			X:        "F",
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	assert.Panics(t, app.Run)
+}
+
+func TestTimeSeries_PanicXSources(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer:  &RowObserver{},
+			X:         "A",
+			ModelTick: true,
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	assert.Panics(t, app.Run)
+}
+
+func TestTimeSeries_Window(t *testing.T) {
+	ts := plot.TimeSeries{
+		Observer: &RowObserver{},
+		Window:   20,
+	}
+
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&ts))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "data.csv")
+	assert.Nil(t, ts.ExportData(path))
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 22, strings.Count(string(content), "\n"))
+}
+
+func TestTimeSeries_Downsampling(t *testing.T) {
+	ts := plot.TimeSeries{
+		Observer:     &RowObserver{},
+		Downsampling: plot.ReservoirDownsampling,
+		MaxPoints:    30,
+	}
+
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&ts).
+		With(&plot.TimeSeries{
+			Observer:     &RowObserver{},
+			Downsampling: plot.LTTBDownsampling,
+			MaxPoints:    30,
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "data.csv")
+	assert.Nil(t, ts.ExportData(path))
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 31, strings.Count(string(content), "\n"))
+}
+
+func TestTimeSeries_Y2(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Y2:       []string{"C"},
+			Y2Axis:   plot.Axis{Format: plot.PercentFormat},
+			Labels:   plot.Labels{Y: "A, B", Y2: "C"},
+		}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Y2:       []string{"C"},
+			Labels:   plot.Labels{Y: "A, B", Y2: "C"},
+			Native:   true,
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestTimeSeries_PanicY2(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Columns:  []string{"A", "B"},
+			Y2:       []string{"C"},
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	assert.Panics(t, app.Run)
+}
+
+func TestTimeSeries_Bands(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Bands:    []plot.Band{{Line: "B", Lower: "A", Upper: "C", Opacity: 0.5}},
+		}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Columns:  []string{"A", "B"},
+			Bands:    []plot.Band{{Line: "B", Spread: "A"}},
+			Y2:       []string{"B"},
+			Native:   true,
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
+func TestTimeSeries_PanicBands(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Bands:    []plot.Band{{Line: "B", Lower: "A"}},
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	assert.Panics(t, app.Run)
+}
+
+func TestTimeSeries_Markers(t *testing.T) {
+	app := app.New()
+	app.TPS = 300
+	ecs.AddResource(app.World, &plot.Annotations{})
+	app.AddSystem(&annotator{Interval: 20})
+	app.AddUISystem((&window.Window{}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Events:   true,
+			VLines:   []plot.Marker{{Value: 50, Label: "Start"}},
+			HLines:   []plot.Marker{{Value: 10, Label: "Threshold"}},
+		}).
+		With(&plot.TimeSeries{
+			Observer: &RowObserver{},
+			Events:   true,
+			HLines:   []plot.Marker{{Value: -5, Label: "Threshold"}},
+			Native:   true,
+		}))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+}
+
 func TestTimeSeries_PanicColumns(t *testing.T) {
 	app := app.New()
 	app.TPS = 300
@@ -85,3 +403,20 @@ func (o *RowObserver) Header() []string {
 func (o *RowObserver) Values(w *ecs.World) []float64 {
 	return []float64{rand.Float64(), rand.Float64() + 1, rand.Float64() + 2}
 }
+
+// annotator adds an event to the Annotations resource in regular intervals.
+type annotator struct {
+	Interval int
+	step     int
+}
+
+func (a *annotator) Initialize(w *ecs.World) {}
+
+func (a *annotator) Update(w *ecs.World) {
+	if a.step%a.Interval == 0 {
+		ecs.GetResource[plot.Annotations](w).Add(float64(a.step), "Event")
+	}
+	a.step++
+}
+
+func (a *annotator) Finalize(w *ecs.World) {}
@@ -0,0 +1,206 @@
+package plot
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark/ecs"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Trajectory plot drawer.
+//
+// Draws the trajectory of two columns of the observer against each other, like in a phase space.
+// Adds one point to the trajectory per update, and draws it as a connected path
+// with fading older segments and a marker for the current state.
+// Particularly useful for dynamical systems, like predator–prey models.
+//
+// Optionally, segments are colored by a third column, like the time, shown by a color bar.
+// Segments with NaN coordinates are left out.
+type Trajectory struct {
+	Observer       observer.Row    // Observer providing a data row per update.
+	X              string          // X column name. Optional. Defaults to first column.
+	Y              string          // Y column name. Optional. Defaults to second column.
+	Color          string          // Column name for mapping segment colors, like the time. Optional, default single color.
+	Palette        palette.Palette // Color palette for color mapping. Optional, default "viridis" (see [NamedPalette]).
+	ColorLim       [2]float64      // Value range for color mapping. Optional, default data range.
+	HideColorBar   bool            // Hides the color bar of color mapping.
+	Fade           float64         // Opacity of the oldest segment, increasing linearly to the current state. Optional, default 0.1. Use 1 for no fading.
+	UpdateInterval int             // Interval for getting data from the the observer, in model ticks. Optional.
+	MaxRows        int             // Maximum number of rows to keep. Zero means unlimited. Optional.
+	XLim           [2]float64      // X axis limits. Optional, default auto.
+	YLim           [2]float64      // Y axis limits. Optional, default auto.
+	Labels         Labels          // Labels for plot and axes. Optional.
+	Style          *Style          // Plot style (colors, fonts, line widths, ...). Optional, default light style.
+	XAxis          Axis            // X axis configuration (scale, tick format, grid, ...). Optional.
+	YAxis          Axis            // Y axis configuration (scale, tick format, grid, ...). Optional.
+	Async          bool            // Renders the plot in a background goroutine, keeping the UI responsive. Optional.
+	SavePath       string          // File for saving the plot with Ctrl+S, format by extension. Optional, default time-stamped PNG.
+	ExportPath     string          // File for exporting the data with Ctrl+E, format by extension. Optional, default time-stamped CSV.
+
+	xIndex     int
+	yIndex     int
+	colorIndex int
+	headers    []string
+
+	steps    []float64
+	xys      plotter.XYs
+	values   []float64
+	scale    float64
+	style    *Style
+	step     int64
+	renderer plotRenderer
+}
+
+// Initialize the drawer.
+func (t *Trajectory) Initialize(w *ecs.World, _ *opengl.Window) {
+	t.Observer.Initialize(w)
+
+	t.headers = t.Observer.Header()
+
+	if len(t.headers) < 2 && (t.X == "" || t.Y == "") {
+		panic("trajectory requires X and Y columns for observers with fewer than two columns")
+	}
+
+	var ok bool
+	t.xIndex, t.yIndex, t.colorIndex = 0, 1, -1
+	if t.X != "" {
+		t.xIndex, ok = find(t.headers, t.X)
+		if !ok {
+			panic(fmt.Sprintf("x column '%s' not found", t.X))
+		}
+	}
+	if t.Y != "" {
+		t.yIndex, ok = find(t.headers, t.Y)
+		if !ok {
+			panic(fmt.Sprintf("y column '%s' not found", t.Y))
+		}
+	}
+	if t.Color != "" {
+		t.colorIndex, ok = find(t.headers, t.Color)
+		if !ok {
+			panic(fmt.Sprintf("color column '%s' not found", t.Color))
+		}
+	}
+	if t.Palette == nil {
+		t.Palette = defaultPalette()
+	}
+	if t.Fade <= 0 {
+		t.Fade = defaultTrajectoryFade
+	}
+
+	t.scale = calcScaleCorrection()
+	t.style = t.Style.resolve()
+	t.step = 0
+	t.renderer = plotRenderer{}
+}
+
+// Update the drawer.
+func (t *Trajectory) Update(w *ecs.World) {
+	t.Observer.Update(w)
+	if t.UpdateInterval <= 1 || t.step%int64(t.UpdateInterval) == 0 {
+		values := t.Observer.Values(w)
+		t.steps = append(t.steps, float64(t.step))
+		t.xys = append(t.xys, plotter.XY{X: values[t.xIndex], Y: values[t.yIndex]})
+		if t.colorIndex >= 0 {
+			t.values = append(t.values, values[t.colorIndex])
+		}
+		if t.MaxRows > 0 && len(t.xys) > t.MaxRows {
+			t.steps = t.steps[len(t.steps)-t.MaxRows:]
+			t.xys = t.xys[len(t.xys)-t.MaxRows:]
+			if t.colorIndex >= 0 {
+				t.values = t.values[len(t.values)-t.MaxRows:]
+			}
+		}
+		t.renderer.Invalidate()
+	}
+	t.step++
+}
+
+// UpdateInputs handles input events of the previous frame update.
+func (t *Trajectory) UpdateInputs(_ *ecs.World, win *opengl.Window) {
+	t.renderer.HandleInputs(win, t.scale)
+	if savePressed(win) {
+		saveWindow(win, t.SavePath, t.scale, t.SaveAs)
+	}
+	if exportPressed(win) {
+		exportWindow(t.ExportPath, t.ExportData)
+	}
+}
+
+// Draw the drawer.
+func (t *Trajectory) Draw(_ *ecs.World, win *opengl.Window) {
+	t.renderer.Draw(win, t.scale, t.Async, t.buildFigure)
+}
+
+// SaveAs saves the plot with its current data to a file of the given size.
+// The file format is selected by the extension. Supported are svg, pdf, eps, png, jpg, jpeg, tif and tiff.
+func (t *Trajectory) SaveAs(path string, width, height vg.Length) error {
+	return t.renderer.Save(path, width, height)
+}
+
+// ExportData writes the current data of the plot to a file, with column headers from the observer.
+// The file format is selected by the extension. Supported are csv and json.
+func (t *Trajectory) ExportData(path string) error {
+	x := dataColumn{Name: t.headers[t.xIndex], Values: make([]float64, len(t.xys))}
+	y := dataColumn{Name: t.headers[t.yIndex], Values: make([]float64, len(t.xys))}
+	for i, pt := range t.xys {
+		x.Values[i] = pt.X
+		y.Values[i] = pt.Y
+	}
+	columns := []dataColumn{{Name: "Tick", Values: t.steps}, x, y}
+	if t.colorIndex >= 0 {
+		columns = append(columns, dataColumn{Name: t.headers[t.colorIndex], Values: t.values})
+	}
+	return exportData(path, columns)
+}
+
+func (t *Trajectory) buildFigure() *figure {
+	p := plot.New()
+	setLabels(p, t.Labels, t.style)
+
+	p.X.Tick.Marker = removeLastTicks{}
+	setAxes(p, t.XAxis, t.YAxis, t.style)
+
+	if t.XLim[0] != 0 || t.XLim[1] != 0 {
+		p.X.Min = t.XLim[0]
+		p.X.Max = t.XLim[1]
+	}
+	if t.YLim[0] != 0 || t.YLim[1] != 0 {
+		p.Y.Min = t.YLim[0]
+		p.Y.Max = t.YLim[1]
+	}
+
+	fig := figure{plot: p}
+
+	lines := t.style.lineStyle(0)
+	lines.Dashes = nil
+	path := &trajectoryPath{
+		XYs:       slices.Clone(t.xys),
+		Colors:    t.Palette.Colors(),
+		LineStyle: lines,
+		Fade:      t.Fade,
+		Marker: draw.GlyphStyle{
+			Color:  t.style.Foreground,
+			Radius: 1.5 * t.style.MarkerSize,
+			Shape:  draw.RingGlyph{},
+		},
+	}
+	if t.colorIndex >= 0 {
+		path.Values = slices.Clone(t.values)
+		path.Mapping = newValueMapping([][]float64{path.Values}, t.ColorLim)
+		if !t.HideColorBar {
+			fig.colorBar = &colorBar{Colors: path.Colors, Min: path.Mapping.Min, Max: path.Mapping.Max}
+		}
+	}
+	p.Add(path)
+
+	fig.series = []namedSeries{{Name: t.headers[t.xIndex] + "/" + t.headers[t.yIndex], XYs: path.XYs}}
+	return &fig
+}
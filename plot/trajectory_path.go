@@ -0,0 +1,94 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Default opacity of the oldest segment of trajectories.
+const defaultTrajectoryFade = 0.1
+
+// trajectoryPath is a plotter for a trajectory, drawn as a path of segments
+// that fade in from the oldest to the newest, with a marker at the newest point.
+// Segments with NaN ends are left out.
+type trajectoryPath struct {
+	XYs       plotter.XYs
+	Values    []float64     // Values for color mapping, per point. Optional.
+	Colors    []color.Color // Palette for color mapping. Required with Values.
+	Mapping   valueMapping  // Value range for color mapping.
+	LineStyle draw.LineStyle
+	Fade      float64 // Opacity of the oldest segment.
+	Marker    draw.GlyphStyle
+}
+
+// color returns the color of the point with the given index.
+func (t *trajectoryPath) color(i int) color.Color {
+	if t.Values != nil {
+		if c := t.Mapping.color(t.Colors, t.Values[i]); c != nil {
+			return c
+		}
+	}
+	return t.LineStyle.Color
+}
+
+// opacity returns the opacity of the segment ending at the point with the given index.
+func (t *trajectoryPath) opacity(i int) float64 {
+	if len(t.XYs) < 3 {
+		return 1
+	}
+	return t.Fade + (1-t.Fade)*float64(i-1)/float64(len(t.XYs)-2)
+}
+
+// Plot implements the Plot method of the plot.Plotter interface.
+func (t *trajectoryPath) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	last := -1
+	for i, pt := range t.XYs {
+		if math.IsNaN(pt.X) || math.IsNaN(pt.Y) {
+			continue
+		}
+		if i > 0 && last == i-1 {
+			prev := t.XYs[i-1]
+			sty := t.LineStyle
+			sty.Color = bandColor(t.color(i), t.opacity(i))
+			c.StrokeLines(sty, c.ClipLinesXY([]vg.Point{
+				{X: trX(prev.X), Y: trY(prev.Y)},
+				{X: trX(pt.X), Y: trY(pt.Y)},
+			})...)
+		}
+		last = i
+	}
+
+	if last < 0 {
+		return
+	}
+	pt := vg.Point{X: trX(t.XYs[last].X), Y: trY(t.XYs[last].Y)}
+	if !c.Contains(pt) {
+		return
+	}
+	fill := t.Marker
+	fill.Color = t.color(last)
+	fill.Shape = draw.CircleGlyph{}
+	c.DrawGlyph(fill, pt)
+	c.DrawGlyph(t.Marker, pt)
+}
+
+// DataRange implements the DataRange method of the plot.DataRanger interface.
+// Ignores points with NaN coordinates.
+func (t *trajectoryPath) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, xmax = math.Inf(1), math.Inf(-1)
+	ymin, ymax = math.Inf(1), math.Inf(-1)
+	for _, pt := range t.XYs {
+		if math.IsNaN(pt.X) || math.IsNaN(pt.Y) {
+			continue
+		}
+		xmin, xmax = math.Min(xmin, pt.X), math.Max(xmax, pt.X)
+		ymin, ymax = math.Min(ymin, pt.Y), math.Max(ymax, pt.Y)
+	}
+	return xmin, xmax, ymin, ymax
+}
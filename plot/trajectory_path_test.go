@@ -0,0 +1,36 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+func TestTrajectoryPath(t *testing.T) {
+	path := trajectoryPath{
+		XYs:       plotter.XYs{{X: 1, Y: 2}, {X: math.NaN(), Y: 0}, {X: -1, Y: 5}, {X: 3, Y: math.NaN()}, {X: 0, Y: 1}},
+		Values:    []float64{0, 1, 2, 3, math.NaN()},
+		Colors:    []color.Color{color.Black, color.White},
+		Mapping:   valueMapping{Min: 0, Max: 4},
+		LineStyle: draw.LineStyle{Color: color.Gray{Y: 128}},
+		Fade:      0.2,
+	}
+
+	xmin, xmax, ymin, ymax := path.DataRange()
+	assert.Equal(t, []float64{-1, 1, 1, 5}, []float64{xmin, xmax, ymin, ymax})
+
+	assert.Equal(t, color.Black, path.color(0))
+	assert.Equal(t, color.White, path.color(3))
+	assert.Equal(t, color.Gray{Y: 128}, path.color(4))
+
+	assert.InDelta(t, 0.2, path.opacity(1), 1e-12)
+	assert.InDelta(t, 0.2+0.8*2.0/3.0, path.opacity(3), 1e-12)
+	assert.InDelta(t, 1.0, path.opacity(4), 1e-12)
+
+	path.XYs = path.XYs[:2]
+	assert.Equal(t, 1.0, path.opacity(1))
+}
@@ -0,0 +1,106 @@
+package plot_test
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/ark-pixel/plot"
+	"github.com/mlange-42/ark-pixel/window"
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/system"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleTrajectory() {
+
+	// Create a new model.
+	app := app.New()
+
+	// Limit the the simulation speed.
+	app.TPS = 30
+
+	// Create a phase-space trajectory plot.
+	// See below for the implementation of the RowObserver.
+	app.AddUISystem((&window.Window{}).
+		With(&plot.Trajectory{
+			Observer: &RowObserver{},
+			X:        "A",
+			Y:        "B",
+			Color:    "C", // Optional column for coloring segments, like the time.
+		}))
+
+	// Add a termination system that ends the simulation.
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+
+	app.Run()
+
+	// Run the simulation.
+	// Due to the use of the OpenGL UI system, the model must be run via [window.Run].
+	// Comment out the code line above, and uncomment the next line to run this example stand-alone.
+
+	// window.Run(app)
+
+	// Output:
+}
+
+func TestTrajectory(t *testing.T) {
+	traj := plot.Trajectory{
+		Observer: &RowObserver{},
+		Color:    "C",
+		Fade:     1,
+		MaxRows:  20,
+	}
+	app := app.New()
+	app.TPS = 300
+	app.AddUISystem((&window.Window{}).With(&traj))
+
+	app.AddSystem(&system.FixedTermination{
+		Steps: 100,
+	})
+	app.Run()
+
+	path := filepath.Join(t.TempDir(), "trajectory.csv")
+	assert.Nil(t, traj.ExportData(path))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 21, len(lines))
+	assert.Equal(t, "Tick,A,B,C", lines[0])
+}
+
+func TestTrajectory_Panic(t *testing.T) {
+	for _, traj := range []plot.Trajectory{
+		{Observer: &RowObserver{}, X: "F"},
+		{Observer: &RowObserver{}, Y: "F"},
+		{Observer: &SingleColumnObserver{}},
+		{Observer: &SingleColumnObserver{}, X: "A"},
+		{Observer: &RowObserver{}, Color: "F"},
+	} {
+		app := app.New()
+		app.TPS = 300
+		app.AddUISystem((&window.Window{}).With(&traj))
+
+		app.AddSystem(&system.FixedTermination{
+			Steps: 100,
+		})
+		assert.Panics(t, app.Run)
+	}
+}
+
+// SingleColumnObserver provides a row with a single column.
+type SingleColumnObserver struct{}
+
+func (o *SingleColumnObserver) Initialize(w *ecs.World) {}
+func (o *SingleColumnObserver) Update(w *ecs.World)     {}
+func (o *SingleColumnObserver) Header() []string {
+	return []string{"A"}
+}
+func (o *SingleColumnObserver) Values(w *ecs.World) []float64 {
+	return []float64{rand.Float64()}
+}
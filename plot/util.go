@@ -5,6 +5,7 @@ import (
 	"image/color"
 
 	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark/ecs"
 	"golang.org/x/image/colornames"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/vg/vgimg"
@@ -24,6 +25,7 @@ type Labels struct {
 	Title string // Plot title
 	X     string // X axis label
 	Y     string // Y axis label
+	Y2    string // Secondary Y axis label, for drawers that support it
 }
 
 // Get the index of an element in a slice.
@@ -41,35 +43,41 @@ func calcScaleCorrection() float64 {
 	return 72.0 / vgimg.DefaultDPI
 }
 
-func setLabels(p *plot.Plot, l Labels) {
+func setLabels(p *plot.Plot, l Labels, s *Style) {
+	p.BackgroundColor = s.Background
+
 	p.Title.Text = l.Title
-	p.Title.TextStyle.Font.Size = 16
-	p.Title.TextStyle.Font.Variant = "Mono"
+	p.Title.TextStyle.Font.Size = s.TitleSize
+	p.Title.TextStyle.Font.Variant = s.Font
+	p.Title.TextStyle.Color = s.Foreground
 
-	p.X.Label.Text = l.X
-	p.X.Label.TextStyle.Font.Size = 14
-	p.X.Label.TextStyle.Font.Variant = "Mono"
+	setAxisStyle(&p.X, l.X, s)
+	setAxisStyle(&p.Y, l.Y, s)
 
-	p.X.Tick.Label.Font.Size = 12
-	p.X.Tick.Label.Font.Variant = "Mono"
+	p.Y.Tick.Marker = paddedTicks{}
+}
 
-	p.Y.Label.Text = l.Y
-	p.Y.Label.TextStyle.Font.Size = 14
-	p.Y.Label.TextStyle.Font.Variant = "Mono"
+func setAxisStyle(a *plot.Axis, label string, s *Style) {
+	a.Label.Text = label
+	a.Label.TextStyle.Font.Size = s.LabelSize
+	a.Label.TextStyle.Font.Variant = s.Font
+	a.Label.TextStyle.Color = s.Foreground
 
-	p.Y.Tick.Label.Font.Size = 12
-	p.Y.Tick.Label.Font.Variant = "Mono"
+	a.Tick.Label.Font.Size = s.TickSize
+	a.Tick.Label.Font.Variant = s.Font
+	a.Tick.Label.Color = s.Foreground
 
-	p.Y.Tick.Marker = paddedTicks{}
+	a.Color = s.Foreground
+	a.Tick.Color = s.Foreground
 }
 
 // Left-pads tick labels to avoid jumping Y axis.
 type paddedTicks struct {
-	plot.DefaultTicks
+	Ticker plot.Ticker // Underlying ticker. Optional, default plot.DefaultTicks.
 }
 
 func (t paddedTicks) Ticks(min, max float64) []plot.Tick {
-	ticks := t.DefaultTicks.Ticks(min, max)
+	ticks := tickerOrDefault(t.Ticker).Ticks(min, max)
 	for i := range ticks {
 		ticks[i].Label = fmt.Sprintf("%*s", 10, ticks[i].Label)
 	}
@@ -78,11 +86,11 @@ func (t paddedTicks) Ticks(min, max float64) []plot.Tick {
 
 // Removes the last tick label to avoid jumping X axis.
 type removeLastTicks struct {
-	plot.DefaultTicks
+	Ticker plot.Ticker // Underlying ticker. Optional, default plot.DefaultTicks.
 }
 
 func (t removeLastTicks) Ticks(min, max float64) []plot.Tick {
-	ticks := t.DefaultTicks.Ticks(min, max)
+	ticks := tickerOrDefault(t.Ticker).Ticks(min, max)
 	for i := range ticks {
 		tick := &ticks[i]
 		if tick.IsMinor() {
@@ -95,12 +103,64 @@ func (t removeLastTicks) Ticks(min, max float64) []plot.Tick {
 	return ticks
 }
 
+func tickerOrDefault(t plot.Ticker) plot.Ticker {
+	if t == nil {
+		return plot.DefaultTicks{}
+	}
+	return t
+}
+
+// gridCoords is a snapshot of the dimensions and cell coordinates of a grid observer.
+// Plotters use it instead of the observer, as figures may be rendered
+// in a background goroutine while the observer is updated.
+type gridCoords struct {
+	Width, Height int
+	XCoords       []float64
+	YCoords       []float64
+}
+
+// update the snapshot from the given dimensions and coordinate functions, re-using its memory.
+func (g *gridCoords) update(width, height int, x, y func(int) float64) {
+	g.Width, g.Height = width, height
+	g.XCoords = g.XCoords[:0]
+	for c := range width {
+		g.XCoords = append(g.XCoords, x(c))
+	}
+	g.YCoords = g.YCoords[:0]
+	for r := range height {
+		g.YCoords = append(g.YCoords, y(r))
+	}
+}
+
+// Dims returns the number of columns and rows.
+func (g *gridCoords) Dims() (int, int) {
+	return g.Width, g.Height
+}
+
+// X returns the coordinate of a column.
+func (g *gridCoords) X(c int) float64 {
+	return g.XCoords[c]
+}
+
+// Y returns the coordinate of a row.
+func (g *gridCoords) Y(r int) float64 {
+	return g.YCoords[r]
+}
+
+// plotGrid is a snapshot of a grid observer, implementing plotter.GridXYZ.
 type plotGrid struct {
-	observer.Grid
+	gridCoords
 	Values []float64
 }
 
+// update the snapshot from the observer, re-using its memory.
+// This is safe as the renderer only builds a new figure after the previous one is rendered.
+func (g *plotGrid) update(obs observer.Grid, w *ecs.World) {
+	width, height := obs.Dims()
+	g.gridCoords.update(width, height, obs.X, obs.Y)
+	g.Values = append(g.Values[:0], obs.Values(w)...)
+}
+
 func (g *plotGrid) Z(c, r int) float64 {
-	w, _ := g.Dims()
-	return g.Values[r*w+c]
+	return g.Values[r*g.Width+c]
 }
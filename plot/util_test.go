@@ -3,6 +3,7 @@ package plot
 import (
 	"testing"
 
+	"github.com/mlange-42/ark/ecs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,3 +18,30 @@ func TestFind(t *testing.T) {
 	assert.False(t, ok)
 	assert.Equal(t, -1, idx)
 }
+
+type testGrid struct {
+	values []float64
+}
+
+func (o *testGrid) Initialize(w *ecs.World)       {}
+func (o *testGrid) Update(w *ecs.World)           {}
+func (o *testGrid) Dims() (int, int)              { return 2, 3 }
+func (o *testGrid) X(c int) float64               { return 10 * float64(c) }
+func (o *testGrid) Y(r int) float64               { return 20 * float64(r) }
+func (o *testGrid) Values(w *ecs.World) []float64 { return o.values }
+
+func TestPlotGridUpdate(t *testing.T) {
+	obs := &testGrid{values: []float64{0, 1, 2, 3, 4, 5}}
+	grid := plotGrid{}
+	grid.update(obs, nil)
+
+	c, r := grid.Dims()
+	assert.Equal(t, 2, c)
+	assert.Equal(t, 3, r)
+	assert.Equal(t, 10.0, grid.X(1))
+	assert.Equal(t, 40.0, grid.Y(2))
+	assert.Equal(t, 3.0, grid.Z(1, 1))
+
+	obs.values[3] = -1
+	assert.Equal(t, 3.0, grid.Z(1, 1))
+}